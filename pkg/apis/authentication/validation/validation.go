@@ -0,0 +1,37 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kubernetes/pkg/apis/authentication"
+)
+
+// ValidateTokenReviewSpec validates the user-provided portion of a TokenReview before it's
+// handed to an authenticator.
+func ValidateTokenReviewSpec(spec authentication.TokenReviewSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(spec.Token) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("token"), ""))
+	}
+	return allErrs
+}
+
+// ValidateTokenReview validates a TokenReview before it's submitted for authentication.
+func ValidateTokenReview(tokenReview *authentication.TokenReview) field.ErrorList {
+	return ValidateTokenReviewSpec(tokenReview.Spec, field.NewPath("spec"))
+}
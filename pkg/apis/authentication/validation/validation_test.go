@@ -0,0 +1,35 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	"k8s.io/kubernetes/pkg/apis/authentication"
+)
+
+func TestValidateTokenReview(t *testing.T) {
+	if errs := ValidateTokenReview(&authentication.TokenReview{
+		Spec: authentication.TokenReviewSpec{Token: "abc"},
+	}); len(errs) != 0 {
+		t.Errorf("expected success, got: %v", errs)
+	}
+
+	if errs := ValidateTokenReview(&authentication.TokenReview{}); len(errs) == 0 {
+		t.Errorf("expected a missing token to be rejected")
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenreview
+
+import (
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/kubernetes/pkg/apis/authentication"
+)
+
+// newTokenReviewStatus builds a TokenReviewStatus from the result of an authenticator call:
+// whether the request authenticated, the resulting user.Info (nil if it didn't), and any error
+// the authenticator returned.
+func newTokenReviewStatus(info user.Info, authenticated bool, err error) authentication.TokenReviewStatus {
+	status := authentication.TokenReviewStatus{Authenticated: authenticated}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	if info != nil {
+		status.User = authentication.UserInfo{
+			Username: info.GetName(),
+			UID:      info.GetUID(),
+			Groups:   info.GetGroups(),
+			Extra:    map[string]authentication.ExtraValue{},
+		}
+		for k, v := range info.GetExtra() {
+			status.User.Extra[k] = authentication.ExtraValue(v)
+		}
+	}
+	return status
+}
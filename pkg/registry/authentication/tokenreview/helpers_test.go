@@ -0,0 +1,50 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenreview
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+func TestNewTokenReviewStatus(t *testing.T) {
+	status := newTokenReviewStatus(&user.DefaultInfo{
+		Name:   "alice",
+		UID:    "123",
+		Groups: []string{"developers"},
+		Extra:  map[string][]string{"scopes": {"read"}},
+	}, true, nil)
+	if !status.Authenticated || status.Error != "" {
+		t.Fatalf("expected a successful status, got %+v", status)
+	}
+	if status.User.Username != "alice" || status.User.UID != "123" {
+		t.Errorf("expected user alice/123, got %+v", status.User)
+	}
+	if got := status.User.Extra["scopes"]; len(got) != 1 || got[0] != "read" {
+		t.Errorf("expected extra scopes=[read], got %v", got)
+	}
+
+	failed := newTokenReviewStatus(nil, false, errors.New("boom"))
+	if failed.Authenticated || failed.Error != "boom" {
+		t.Errorf("expected a failed status carrying the error, got %+v", failed)
+	}
+	if failed.User.Username != "" {
+		t.Errorf("expected no user on failure, got %+v", failed.User)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/registry/rest"
 	"k8s.io/kubernetes/pkg/apis/authentication"
+	"k8s.io/kubernetes/pkg/apis/authentication/validation"
 )
 
 type REST struct {
@@ -50,8 +51,8 @@ func (r *REST) Create(ctx genericapirequest.Context, obj runtime.Object, createV
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("namespace is not allowed on this type: %v", namespace))
 	}
 
-	if len(tokenReview.Spec.Token) == 0 {
-		return nil, apierrors.NewBadRequest(fmt.Sprintf("token is required for TokenReview in authentication"))
+	if errs := validation.ValidateTokenReview(tokenReview); len(errs) > 0 {
+		return nil, apierrors.NewInvalid(authentication.Kind(tokenReview.Kind), tokenReview.Name, errs)
 	}
 
 	if r.tokenAuthenticator == nil {
@@ -63,21 +64,7 @@ func (r *REST) Create(ctx genericapirequest.Context, obj runtime.Object, createV
 	fakeReq.Header.Add("Authorization", "Bearer "+tokenReview.Spec.Token)
 
 	tokenUser, ok, err := r.tokenAuthenticator.AuthenticateRequest(fakeReq)
-	tokenReview.Status.Authenticated = ok
-	if err != nil {
-		tokenReview.Status.Error = err.Error()
-	}
-	if tokenUser != nil {
-		tokenReview.Status.User = authentication.UserInfo{
-			Username: tokenUser.GetName(),
-			UID:      tokenUser.GetUID(),
-			Groups:   tokenUser.GetGroups(),
-			Extra:    map[string]authentication.ExtraValue{},
-		}
-		for k, v := range tokenUser.GetExtra() {
-			tokenReview.Status.User.Extra[k] = authentication.ExtraValue(v)
-		}
-	}
+	tokenReview.Status = newTokenReviewStatus(tokenUser, ok, err)
 
 	return tokenReview, nil
 }
@@ -116,6 +116,9 @@ func (m ExtraValue) MarshalTo(dAtA []byte) (int, error) {
 }
 
 func (m *TokenReview) Marshal() (dAtA []byte, err error) {
+	if m == nil {
+		return nil, nil
+	}
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -158,6 +161,9 @@ func (m *TokenReview) MarshalTo(dAtA []byte) (int, error) {
 }
 
 func (m *TokenReviewSpec) Marshal() (dAtA []byte, err error) {
+	if m == nil {
+		return nil, nil
+	}
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -180,6 +186,9 @@ func (m *TokenReviewSpec) MarshalTo(dAtA []byte) (int, error) {
 }
 
 func (m *TokenReviewStatus) Marshal() (dAtA []byte, err error) {
+	if m == nil {
+		return nil, nil
+	}
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -218,6 +227,9 @@ func (m *TokenReviewStatus) MarshalTo(dAtA []byte) (int, error) {
 }
 
 func (m *UserInfo) Marshal() (dAtA []byte, err error) {
+	if m == nil {
+		return nil, nil
+	}
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
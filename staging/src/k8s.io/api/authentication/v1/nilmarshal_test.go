@@ -0,0 +1,45 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import "testing"
+
+// TestMarshalNilPointerDoesNotPanic checks that Marshal on a nil pointer of each generated
+// top-level message returns (nil, nil) instead of panicking by dereferencing a nil receiver's
+// fields, since a caller holding a nil message (e.g. an unset optional field) may call Marshal
+// without having checked for nil first.
+func TestMarshalNilPointerDoesNotPanic(t *testing.T) {
+	var tokenReview *TokenReview
+	if dAtA, err := tokenReview.Marshal(); dAtA != nil || err != nil {
+		t.Errorf("expected (nil, nil) for a nil *TokenReview, got (%v, %v)", dAtA, err)
+	}
+
+	var tokenReviewSpec *TokenReviewSpec
+	if dAtA, err := tokenReviewSpec.Marshal(); dAtA != nil || err != nil {
+		t.Errorf("expected (nil, nil) for a nil *TokenReviewSpec, got (%v, %v)", dAtA, err)
+	}
+
+	var tokenReviewStatus *TokenReviewStatus
+	if dAtA, err := tokenReviewStatus.Marshal(); dAtA != nil || err != nil {
+		t.Errorf("expected (nil, nil) for a nil *TokenReviewStatus, got (%v, %v)", dAtA, err)
+	}
+
+	var userInfo *UserInfo
+	if dAtA, err := userInfo.Marshal(); dAtA != nil || err != nil {
+		t.Errorf("expected (nil, nil) for a nil *UserInfo, got (%v, %v)", dAtA, err)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "testing"
+
+func benchmarkUserInfo() UserInfo {
+	return UserInfo{
+		Username: "alice",
+		UID:      "12345",
+		Groups:   []string{"system:authenticated", "developers", "admins"},
+		Extra: map[string]ExtraValue{
+			"scopes": {"read", "write"},
+		},
+	}
+}
+
+func benchmarkTokenReview() TokenReview {
+	return TokenReview{
+		Spec: TokenReviewSpec{Token: "abcdefghijklmnopqrstuvwxyz0123456789"},
+		Status: TokenReviewStatus{
+			Authenticated: true,
+			User:          benchmarkUserInfo(),
+		},
+	}
+}
+
+func BenchmarkUserInfoMarshal(b *testing.B) {
+	info := benchmarkUserInfo()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := info.Marshal(); err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkUserInfoUnmarshal(b *testing.B) {
+	info := benchmarkUserInfo()
+	dAtA, err := info.Marshal()
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var info UserInfo
+		if err := info.Unmarshal(dAtA); err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkUserInfoEncodedSize(b *testing.B) {
+	info := benchmarkUserInfo()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = info.EncodedSize()
+	}
+}
+
+func BenchmarkTokenReviewMarshal(b *testing.B) {
+	tokenReview := benchmarkTokenReview()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := tokenReview.Marshal(); err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkTokenReviewUnmarshal(b *testing.B) {
+	tokenReview := benchmarkTokenReview()
+	dAtA, err := tokenReview.Marshal()
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var tokenReview TokenReview
+		if err := tokenReview.Unmarshal(dAtA); err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
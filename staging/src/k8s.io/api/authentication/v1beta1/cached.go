@@ -0,0 +1,51 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "sync"
+
+// CachedUserInfo wraps a UserInfo that the caller promises not to mutate after construction,
+// and memoizes its Marshal output. This is useful for UserInfo values that get marshaled
+// repeatedly (e.g. attached to every request in a long-lived cache), where re-running the
+// generated Marshal on every call is wasted work.
+type CachedUserInfo struct {
+	info UserInfo
+
+	once       sync.Once
+	dAtA       []byte
+	marshalErr error
+}
+
+// NewCachedUserInfo returns a CachedUserInfo wrapping info. info must not be mutated after
+// being passed in; doing so invalidates the cache without detection.
+func NewCachedUserInfo(info UserInfo) *CachedUserInfo {
+	return &CachedUserInfo{info: info}
+}
+
+// Info returns the wrapped UserInfo.
+func (c *CachedUserInfo) Info() UserInfo {
+	return c.info
+}
+
+// Marshal returns the marshaled form of the wrapped UserInfo, computing and caching it on the
+// first call.
+func (c *CachedUserInfo) Marshal() ([]byte, error) {
+	c.once.Do(func() {
+		c.dAtA, c.marshalErr = c.info.Marshal()
+	})
+	return c.dAtA, c.marshalErr
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCachedUserInfoMarshal(t *testing.T) {
+	c := NewCachedUserInfo(UserInfo{Username: "alice", UID: "123"})
+
+	wantInfo := UserInfo{Username: "alice", UID: "123"}
+	want, err := wantInfo.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		got, err := c.Marshal()
+		if err != nil {
+			t.Fatalf("unexpected error on call %d: %v", i, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("call %d: expected %v, got %v", i, want, got)
+		}
+	}
+}
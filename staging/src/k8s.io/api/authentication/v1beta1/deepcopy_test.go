@@ -0,0 +1,49 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+)
+
+// TestTokenReviewDeepCopyDoesNotAliasExtra guards against a regression where mutating a
+// DeepCopy's Status.User.Extra would reach back into the original TokenReview's slices.
+func TestTokenReviewDeepCopyDoesNotAliasExtra(t *testing.T) {
+	original := &TokenReview{
+		Status: TokenReviewStatus{
+			Authenticated: true,
+			User: UserInfo{
+				Username: "alice",
+				Groups:   []string{"admins"},
+				Extra: map[string]ExtraValue{
+					"k": {"v1", "v2"},
+				},
+			},
+		},
+	}
+
+	copied := original.DeepCopy()
+	copied.Status.User.Extra["k"][0] = "mutated"
+	copied.Status.User.Groups[0] = "mutated"
+
+	if got := original.Status.User.Extra["k"][0]; got != "v1" {
+		t.Errorf("expected original Extra[\"k\"][0] to remain %q, got %q", "v1", got)
+	}
+	if got := original.Status.User.Groups[0]; got != "admins" {
+		t.Errorf("expected original Groups[0] to remain %q, got %q", "admins", got)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DiffUserInfo unmarshals a and b as marshaled UserInfo messages and reports, field by field,
+// how the two identities differ. It's meant for debugging why two UserInfos that are supposedly
+// equal produced different bytes -- for example, a cache hit that should have been a miss -- not
+// for use on a hot path. An empty string means a and b decode to the same Username and UID and
+// the same sets of Groups and per-key Extra values; ordering and duplicate entries within Groups
+// or an Extra value are not compared.
+func DiffUserInfo(a, b []byte) (string, error) {
+	var userA, userB UserInfo
+	if err := userA.Unmarshal(a); err != nil {
+		return "", fmt.Errorf("unmarshaling a: %v", err)
+	}
+	if err := userB.Unmarshal(b); err != nil {
+		return "", fmt.Errorf("unmarshaling b: %v", err)
+	}
+
+	var lines []string
+	if userA.Username != userB.Username {
+		lines = append(lines, fmt.Sprintf("username: %q != %q", userA.Username, userB.Username))
+	}
+	if userA.UID != userB.UID {
+		lines = append(lines, fmt.Sprintf("uid: %q != %q", userA.UID, userB.UID))
+	}
+	if groupDiff := diffStringSets(userA.Groups, userB.Groups); groupDiff != "" {
+		lines = append(lines, fmt.Sprintf("groups: %s", groupDiff))
+	}
+	if extraDiff := diffExtra(userA.Extra, userB.Extra); extraDiff != "" {
+		lines = append(lines, fmt.Sprintf("extra: %s", extraDiff))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// diffStringSets compares a and b as unordered sets, returning a human-readable description of
+// which elements are only on one side, or "" if they contain the same elements.
+func diffStringSets(a, b []string) string {
+	onlyInA, onlyInB := setDifference(a, b), setDifference(b, a)
+	if len(onlyInA) == 0 && len(onlyInB) == 0 {
+		return ""
+	}
+	var parts []string
+	if len(onlyInA) > 0 {
+		parts = append(parts, fmt.Sprintf("only in a: %v", onlyInA))
+	}
+	if len(onlyInB) > 0 {
+		parts = append(parts, fmt.Sprintf("only in b: %v", onlyInB))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// setDifference returns the sorted elements of a that don't appear in b.
+func setDifference(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, v := range b {
+		inB[v] = true
+	}
+	var diff []string
+	for _, v := range a {
+		if !inB[v] {
+			diff = append(diff, v)
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
+
+// diffExtra compares two Extra maps key by key, returning a human-readable description of which
+// keys are missing from either side and which shared keys have differing values, or "" if the
+// maps are equivalent.
+func diffExtra(a, b map[string]ExtraValue) string {
+	keys := make(map[string]bool, len(a)+len(b))
+	for key := range a {
+		keys[key] = true
+	}
+	for key := range b {
+		keys[key] = true
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	var parts []string
+	for _, key := range sortedKeys {
+		valuesA, okA := a[key]
+		valuesB, okB := b[key]
+		switch {
+		case okA && !okB:
+			parts = append(parts, fmt.Sprintf("%q: only in a (%v)", key, []string(valuesA)))
+		case !okA && okB:
+			parts = append(parts, fmt.Sprintf("%q: only in b (%v)", key, []string(valuesB)))
+		default:
+			if valueDiff := diffStringSets(valuesA, valuesB); valueDiff != "" {
+				parts = append(parts, fmt.Sprintf("%q: %s", key, valueDiff))
+			}
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"strings"
+	"testing"
+)
+
+func marshalOrFatal(t *testing.T, info UserInfo) []byte {
+	t.Helper()
+	data, err := info.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return data
+}
+
+func TestDiffUserInfoIdentical(t *testing.T) {
+	info := benchmarkUserInfo()
+
+	diff, err := DiffUserInfo(marshalOrFatal(t, info), marshalOrFatal(t, info))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff != "" {
+		t.Errorf("expected no diff between identical UserInfos, got %q", diff)
+	}
+}
+
+func TestDiffUserInfoSingleGroupDiffers(t *testing.T) {
+	a := benchmarkUserInfo()
+	b := benchmarkUserInfo()
+	b.Groups = []string{"system:authenticated", "developers", "ops"}
+
+	diff, err := DiffUserInfo(marshalOrFatal(t, a), marshalOrFatal(t, b))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff == "" {
+		t.Fatalf("expected a diff, got none")
+	}
+	for _, want := range []string{"groups:", "admins", "ops"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("expected diff to mention %q, got %q", want, diff)
+		}
+	}
+}
+
+func TestDiffUserInfoSingleExtraValueDiffers(t *testing.T) {
+	a := benchmarkUserInfo()
+	b := benchmarkUserInfo()
+	b.Extra = map[string]ExtraValue{"scopes": {"read", "admin"}}
+
+	diff, err := DiffUserInfo(marshalOrFatal(t, a), marshalOrFatal(t, b))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff == "" {
+		t.Fatalf("expected a diff, got none")
+	}
+	for _, want := range []string{"extra:", "scopes", "write", "admin"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("expected diff to mention %q, got %q", want, diff)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// DumpTokenReview renders a raw TokenReview protobuf payload as a human-readable string, for
+// operators debugging webhook traffic without protoc tooling. data is decoded field by field, so
+// a truncated or otherwise corrupt payload still yields whatever fields were read before the
+// failure, returned alongside the decode error rather than discarded.
+func DumpTokenReview(data []byte) (string, error) {
+	var review TokenReview
+	err := review.Unmarshal(data)
+	return review.String(), err
+}
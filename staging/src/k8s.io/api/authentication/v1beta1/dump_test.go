@@ -0,0 +1,66 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDumpTokenReviewValid(t *testing.T) {
+	review := TokenReview{
+		Spec: TokenReviewSpec{Token: "abc123"},
+		Status: TokenReviewStatus{
+			Authenticated: true,
+			User:          UserInfo{Username: "alice"},
+		},
+	}
+	dAtA, err := review.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+
+	dump, err := DumpTokenReview(dAtA)
+	if err != nil {
+		t.Fatalf("unexpected error dumping a valid payload: %v", err)
+	}
+	if strings.Contains(dump, "abc123") {
+		t.Errorf("expected the dump to redact the token, got %q", dump)
+	}
+	if !strings.Contains(dump, "alice") {
+		t.Errorf("expected the dump to mention the username, got %q", dump)
+	}
+}
+
+func TestDumpTokenReviewTruncated(t *testing.T) {
+	review := TokenReview{
+		Spec: TokenReviewSpec{Token: "abc123"},
+		Status: TokenReviewStatus{
+			Authenticated: true,
+			User:          UserInfo{Username: "alice"},
+		},
+	}
+	dAtA, err := review.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+
+	_, err = DumpTokenReview(dAtA[:len(dAtA)-1])
+	if err == nil {
+		t.Errorf("expected a truncated payload to produce a decode error")
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// UserInfoExtraEmailKey is the well-known Extra key under which an authenticator can report a
+// user's email address. UserInfo's wire format only reserves fields for Username, UID, Groups,
+// and Extra (see types.go); Extra is the extension point the API already provides for exactly
+// this kind of additional attribute, so a new top-level Email field isn't added here -- that
+// would require a new API version, since this type's protobuf encoding is part of a released
+// API.
+const UserInfoExtraEmailKey = "email.authentication.k8s.io/email"
+
+// SetEmail records email as the user's email address in info.Extra, creating the map if it is
+// nil.
+func (info *UserInfo) SetEmail(email string) {
+	if info.Extra == nil {
+		info.Extra = map[string]ExtraValue{}
+	}
+	info.Extra[UserInfoExtraEmailKey] = ExtraValue{email}
+}
+
+// Email returns the user's email address from info.Extra, and whether one was set.
+func (info *UserInfo) Email() (string, bool) {
+	values, ok := info.Extra[UserInfoExtraEmailKey]
+	if !ok || len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "testing"
+
+func TestUserInfoEmail(t *testing.T) {
+	var info UserInfo
+	if _, ok := info.Email(); ok {
+		t.Errorf("expected no email on a zero-value UserInfo")
+	}
+
+	info.SetEmail("alice@example.com")
+	email, ok := info.Email()
+	if !ok || email != "alice@example.com" {
+		t.Errorf("expected email alice@example.com, got %q (ok=%v)", email, ok)
+	}
+
+	dAtA, err := info.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var roundTripped UserInfo
+	if err := roundTripped.Unmarshal(dAtA); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if email, ok := roundTripped.Email(); !ok || email != "alice@example.com" {
+		t.Errorf("expected email to survive a proto round-trip, got %q (ok=%v)", email, ok)
+	}
+}
@@ -0,0 +1,27 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// EncodedSize returns the number of bytes Marshal would produce for m, without allocating or
+// otherwise marshaling the message. It exists for callers doing admission/size budgeting that
+// want the wire size up front, so they don't need to marshal (and discard the result) just to
+// learn how big the encoding would be. It's a thin, explicitly-named wrapper around Size(),
+// which is already a zero-allocation walk of the message and is the canonical estimator this
+// method defers to; see BenchmarkUserInfoEncodedSize.
+func (m *UserInfo) EncodedSize() int {
+	return m.Size()
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "testing"
+
+func TestUserInfoEncodedSizeMatchesMarshalLength(t *testing.T) {
+	tests := map[string]UserInfo{
+		"empty":        {},
+		"usernameOnly": {Username: "alice"},
+		"withGroups": {
+			Username: "alice",
+			UID:      "12345",
+			Groups:   []string{"system:authenticated", "developers"},
+		},
+		"withExtras": benchmarkUserInfo(),
+	}
+	for name, info := range tests {
+		t.Run(name, func(t *testing.T) {
+			dAtA, err := info.Marshal()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got, want := info.EncodedSize(), len(dAtA); got != want {
+				t.Errorf("EncodedSize() = %d, want len(Marshal()) = %d", got, want)
+			}
+			if got, want := info.Size(), len(dAtA); got != want {
+				t.Errorf("Size() = %d, want len(Marshal()) = %d", got, want)
+			}
+		})
+	}
+}
+
+func TestUserInfoEncodedSizeDoesNotAllocate(t *testing.T) {
+	info := benchmarkUserInfo()
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = info.EncodedSize()
+	})
+	if allocs != 0 {
+		t.Errorf("expected EncodedSize to be allocation-free, got %v allocs/op", allocs)
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// NewExtra converts a plain map[string][]string into the map[string]ExtraValue UserInfo.Extra
+// expects, wrapping each value slice so callers building a UserInfo don't have to spell
+// ExtraValue(...) themselves. A nil value slice becomes a nil ExtraValue, not an empty one, to
+// round trip cleanly through ExtraToStringMap. It's the inverse of ExtraToStringMap.
+func NewExtra(m map[string][]string) map[string]ExtraValue {
+	if m == nil {
+		return nil
+	}
+	extra := make(map[string]ExtraValue, len(m))
+	for key, values := range m {
+		if values == nil {
+			extra[key] = nil
+			continue
+		}
+		extra[key] = ExtraValue(values)
+	}
+	return extra
+}
+
+// ExtraToStringMap converts UserInfo.Extra back into a plain map[string][]string, for callers
+// that don't otherwise need the ExtraValue type. It's the inverse of NewExtra.
+func ExtraToStringMap(extra map[string]ExtraValue) map[string][]string {
+	if extra == nil {
+		return nil
+	}
+	m := make(map[string][]string, len(extra))
+	for key, values := range extra {
+		if values == nil {
+			m[key] = nil
+			continue
+		}
+		m[key] = []string(values)
+	}
+	return m
+}
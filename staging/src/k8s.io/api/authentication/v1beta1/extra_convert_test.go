@@ -0,0 +1,57 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewExtraRoundTripsThroughExtraToStringMap(t *testing.T) {
+	in := map[string][]string{
+		"scopes": {"read", "write"},
+		"empty":  {},
+		"nil":    nil,
+	}
+
+	extra := NewExtra(in)
+	want := map[string]ExtraValue{
+		"scopes": {"read", "write"},
+		"empty":  {},
+		"nil":    nil,
+	}
+	if !reflect.DeepEqual(extra, want) {
+		t.Fatalf("NewExtra(%v) = %v, want %v", in, extra, want)
+	}
+
+	out := ExtraToStringMap(extra)
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("ExtraToStringMap(NewExtra(%v)) = %v, want %v", in, out, in)
+	}
+}
+
+func TestNewExtraNil(t *testing.T) {
+	if extra := NewExtra(nil); extra != nil {
+		t.Errorf("expected NewExtra(nil) to return nil, got %v", extra)
+	}
+}
+
+func TestExtraToStringMapNil(t *testing.T) {
+	if m := ExtraToStringMap(nil); m != nil {
+		t.Errorf("expected ExtraToStringMap(nil) to return nil, got %v", m)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "sort"
+
+// MarshalWithExtraOrder is like Marshal, but serializes the Extra map entries in the order
+// given by keyOrder instead of the generated Marshal's fixed alphabetical order. Keys present
+// in m.Extra but not listed in keyOrder are appended afterwards, sorted alphabetically, so the
+// output still covers every entry. The wire format is unaffected - map field ordering carries
+// no semantic meaning in protobuf - this only exists to produce deterministic, caller-controlled
+// byte output for things like content-addressed caching or diff-friendly fixtures.
+func (m *UserInfo) MarshalWithExtraOrder(keyOrder []string) ([]byte, error) {
+	withoutExtra := *m
+	withoutExtra.Extra = nil
+	head, err := withoutExtra.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if len(m.Extra) == 0 {
+		return head, nil
+	}
+
+	seen := make(map[string]bool, len(m.Extra))
+	keys := make([]string, 0, len(m.Extra))
+	for _, k := range keyOrder {
+		if _, ok := m.Extra[k]; ok && !seen[k] {
+			keys = append(keys, k)
+			seen[k] = true
+		}
+	}
+	remaining := make([]string, 0, len(m.Extra)-len(keys))
+	for k := range m.Extra {
+		if !seen[k] {
+			remaining = append(remaining, k)
+		}
+	}
+	sort.Strings(remaining)
+	keys = append(keys, remaining...)
+
+	out := append([]byte{}, head...)
+	for _, k := range keys {
+		entry, err := marshalExtraEntry(k, m.Extra[k])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, entry...)
+	}
+	return out, nil
+}
+
+// RangeExtraSorted invokes fn once per m.Extra entry in lexicographic key order, centralizing
+// the sort-then-iterate pattern MarshalTo and String each do independently, for callers (logging,
+// hashing) that need a deterministic traversal without duplicating it themselves.
+func (m *UserInfo) RangeExtraSorted(fn func(key string, value ExtraValue)) {
+	keys := make([]string, 0, len(m.Extra))
+	for k := range m.Extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fn(k, m.Extra[k])
+	}
+}
+
+// marshalExtraEntry encodes a single UserInfo.Extra map entry (field 4) the same way the
+// generated MarshalTo does, but as a standalone, appendable chunk.
+func marshalExtraEntry(k string, v ExtraValue) ([]byte, error) {
+	msgSize := v.Size()
+	msgSize += 1 + sovGenerated(uint64(msgSize))
+	mapSize := 1 + len(k) + sovGenerated(uint64(len(k))) + msgSize
+
+	dAtA := make([]byte, 1+sovGenerated(uint64(mapSize))+mapSize)
+	i := 0
+	dAtA[i] = 0x22
+	i++
+	i = encodeVarintGenerated(dAtA, i, uint64(mapSize))
+	dAtA[i] = 0xa
+	i++
+	i = encodeVarintGenerated(dAtA, i, uint64(len(k)))
+	i += copy(dAtA[i:], k)
+	dAtA[i] = 0x12
+	i++
+	i = encodeVarintGenerated(dAtA, i, uint64(v.Size()))
+	n, err := v.MarshalTo(dAtA[i:])
+	if err != nil {
+		return nil, err
+	}
+	i += n
+	return dAtA[:i], nil
+}
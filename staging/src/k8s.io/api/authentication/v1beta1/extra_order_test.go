@@ -0,0 +1,79 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUserInfoMarshalWithExtraOrder(t *testing.T) {
+	in := &UserInfo{
+		Username: "alice",
+		Extra: map[string]ExtraValue{
+			"a": {"1"},
+			"b": {"2"},
+			"c": {"3"},
+		},
+	}
+
+	dAtA, err := in.MarshalWithExtraOrder([]string{"c", "a", "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := &UserInfo{}
+	if err := out.Unmarshal(dAtA); err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("expected round-trip to be lossless regardless of key order, got %#v", out)
+	}
+
+	// unlisted keys should still be included.
+	partial, err := in.MarshalWithExtraOrder([]string{"b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out2 := &UserInfo{}
+	if err := out2.Unmarshal(partial); err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+	if len(out2.Extra) != 3 {
+		t.Errorf("expected all 3 Extra entries to survive a partial key order, got %d", len(out2.Extra))
+	}
+}
+
+func TestUserInfoRangeExtraSorted(t *testing.T) {
+	in := &UserInfo{
+		Extra: map[string]ExtraValue{
+			"zebra": {"1"},
+			"apple": {"2"},
+			"mango": {"3"},
+		},
+	}
+
+	var got []string
+	in.RangeExtraSorted(func(key string, value ExtraValue) {
+		got = append(got, key)
+	})
+
+	want := []string{"apple", "mango", "zebra"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected keys in lexicographic order %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// extraHeaderPrefix is the conventional HTTP header prefix authenticating proxies use to
+// forward UserInfo.Extra entries, one header occurrence per value (see
+// k8s.io/client-go/transport.SetAuthProxyHeaders and
+// k8s.io/apiserver/pkg/authentication/request/headerrequest). An Extra key can contain
+// characters that aren't valid in an HTTP header name, so it's URL-escaped onto the header
+// name and unescaped back on the way in; like those two existing implementations, keys are
+// always lowercased, so a key comes back the same however HTTP re-cases the header name, and a
+// key containing upper-case letters comes back lowercased.
+const extraHeaderPrefix = "X-Remote-Extra-"
+
+// ExtraToHeaders renders extra as the "X-Remote-Extra-<url-escaped key>" headers an
+// authenticating proxy forwards downstream, one header occurrence per value. It's the inverse
+// of HeadersToExtra.
+func ExtraToHeaders(extra map[string]ExtraValue) http.Header {
+	headers := http.Header{}
+	for key, values := range extra {
+		headerName := extraHeaderPrefix + url.QueryEscape(strings.ToLower(key))
+		for _, value := range values {
+			headers.Add(headerName, value)
+		}
+	}
+	return headers
+}
+
+// HeadersToExtra recovers the Extra map that ExtraToHeaders encoded into headers. Header name
+// matching is case-insensitive, and keys come back lowercased regardless of how they round
+// tripped through HTTP's header-name canonicalization. A header whose suffix doesn't decode as
+// a valid URL escape is skipped rather than failing the whole conversion.
+func HeadersToExtra(headers http.Header) map[string]ExtraValue {
+	extra := map[string]ExtraValue{}
+	for headerName, values := range headers {
+		if !strings.HasPrefix(strings.ToLower(headerName), strings.ToLower(extraHeaderPrefix)) {
+			continue
+		}
+		key, err := url.QueryUnescape(strings.ToLower(headerName[len(extraHeaderPrefix):]))
+		if err != nil {
+			continue
+		}
+		extra[key] = append(extra[key], values...)
+	}
+	return extra
+}
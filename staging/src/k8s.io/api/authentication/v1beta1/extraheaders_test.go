@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestExtraToHeaders(t *testing.T) {
+	headers := ExtraToHeaders(map[string]ExtraValue{
+		"scopes":        {"read", "write"},
+		"example.com/k": {"v"},
+	})
+
+	if got := headers["X-Remote-Extra-Scopes"]; !reflect.DeepEqual(got, []string{"read", "write"}) {
+		t.Errorf("expected multi-value extra to become repeated header occurrences, got %v", got)
+	}
+
+	escapedHeaderName := http.CanonicalHeaderKey("X-Remote-Extra-" + url.QueryEscape("example.com/k"))
+	if got := headers[escapedHeaderName]; !reflect.DeepEqual(got, []string{"v"}) {
+		t.Errorf("expected a key needing escaping to be present under header %q, got headers %v", escapedHeaderName, headers)
+	}
+}
+
+func TestHeadersToExtra(t *testing.T) {
+	headers := http.Header{}
+	headers.Add("X-Remote-Extra-Scopes", "read")
+	headers.Add("X-Remote-Extra-Scopes", "write")
+	headers.Add("X-Remote-Extra-Example.com%2Fk", "v")
+	headers.Add("X-Remote-User", "alice") // not an Extra header, should be ignored
+
+	extra := HeadersToExtra(headers)
+
+	if got := extra["scopes"]; !reflect.DeepEqual(got, ExtraValue{"read", "write"}) {
+		t.Errorf("expected scopes=[read write], got %v", got)
+	}
+	if got := extra["example.com/k"]; !reflect.DeepEqual(got, ExtraValue{"v"}) {
+		t.Errorf("expected the escaped key to decode back to example.com/k, got %v (full extra: %v)", got, extra)
+	}
+	if _, ok := extra["user"]; ok {
+		t.Errorf("expected non-Extra headers to be ignored, got %v", extra)
+	}
+}
+
+func TestExtraHeadersRoundTrip(t *testing.T) {
+	original := map[string]ExtraValue{
+		"scopes":          {"read", "write"},
+		"example.com/key": {"one", "two"},
+		"UPPERCASE":       {"value"},
+	}
+
+	headers := ExtraToHeaders(original)
+	got := HeadersToExtra(headers)
+
+	want := map[string]ExtraValue{
+		"scopes":          {"read", "write"},
+		"example.com/key": {"one", "two"},
+		"uppercase":       {"value"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip through headers changed the Extra map: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"io"
+)
+
+// userInfoExtraFieldNumber is UserInfo.Extra's field number in the generated wire format (see
+// generated.pb.go); each map entry is encoded as a separate occurrence of this field.
+const userInfoExtraFieldNumber = 4
+
+// countTopLevelField returns how many times fieldNum appears as a top-level field tag in dAtA,
+// without otherwise decoding the payload. Skipping over other fields is bounded to maxGroupDepth
+// levels of group nesting; see skipGeneratedWithDepthLimit.
+func countTopLevelField(dAtA []byte, fieldNum int32, maxGroupDepth int) (int, error) {
+	l := len(dAtA)
+	iNdEx := 0
+	count := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		if int32(wire>>3) == fieldNum {
+			count++
+		}
+		skippy, err := skipGeneratedWithDepthLimit(dAtA[preIndex:], maxGroupDepth)
+		if err != nil {
+			return 0, err
+		}
+		if skippy < 0 {
+			return 0, ErrInvalidLengthGenerated
+		}
+		iNdEx = preIndex + skippy
+	}
+	return count, nil
+}
+
+// checkExtraEntryLimit returns an error if dAtA's UserInfo.Extra map would decode to more than
+// maxEntries entries. maxGroupDepth bounds how deeply nested groups may be while scanning for
+// Extra field tags; see skipGeneratedWithDepthLimit.
+func checkExtraEntryLimit(dAtA []byte, maxEntries, maxGroupDepth int) error {
+	count, err := countTopLevelField(dAtA, userInfoExtraFieldNumber, maxGroupDepth)
+	if err != nil {
+		return err
+	}
+	if count > maxEntries {
+		return fmt.Errorf("proto: UserInfo.Extra has %d entries, which exceeds the limit of %d", count, maxEntries)
+	}
+	return nil
+}
+
+// UnmarshalWithExtraLimit is like Unmarshal, but returns an error if dAtA's Extra map would
+// decode to more than maxExtraEntries entries. This guards against a crafted payload using an
+// unbounded Extra map to exhaust memory, without having to duplicate the generated
+// map-decoding logic just to count entries.
+func (m *UserInfo) UnmarshalWithExtraLimit(dAtA []byte, maxExtraEntries int) error {
+	if err := checkExtraEntryLimit(dAtA, maxExtraEntries, defaultMaxGroupDepth); err != nil {
+		return err
+	}
+	return m.Unmarshal(dAtA)
+}
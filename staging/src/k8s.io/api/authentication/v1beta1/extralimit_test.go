@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "testing"
+
+func TestUserInfoUnmarshalWithExtraLimit(t *testing.T) {
+	info := UserInfo{Extra: map[string]ExtraValue{
+		"a": {"1"},
+		"b": {"2"},
+		"c": {"3"},
+	}}
+	dAtA, err := info.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var within UserInfo
+	if err := within.UnmarshalWithExtraLimit(dAtA, 3); err != nil {
+		t.Errorf("expected a limit equal to the entry count to succeed, got: %v", err)
+	}
+
+	var exceeded UserInfo
+	if err := exceeded.UnmarshalWithExtraLimit(dAtA, 2); err == nil {
+		t.Errorf("expected a limit below the entry count to fail")
+	}
+}
+
+func TestUnmarshalOptionsMaxUserInfoExtraEntries(t *testing.T) {
+	info := UserInfo{Extra: map[string]ExtraValue{"a": {"1"}, "b": {"2"}}}
+	dAtA, err := info.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var exceeded UserInfo
+	opts := UnmarshalOptions{MaxUserInfoExtraEntries: 1}
+	if err := opts.Unmarshal(&exceeded, dAtA); err == nil {
+		t.Errorf("expected MaxUserInfoExtraEntries to reject an over-limit payload")
+	}
+
+	var tokenReview TokenReview
+	if err := opts.Unmarshal(&tokenReview, []byte{}); err != nil {
+		t.Errorf("expected MaxUserInfoExtraEntries to have no effect on non-UserInfo types, got: %v", err)
+	}
+}
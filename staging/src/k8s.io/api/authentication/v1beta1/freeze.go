@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// FrozenUserInfo is a read-only view over a UserInfo, safe to share across goroutines or hold in
+// a long-lived identity cache. Its accessors never return the slices or maps backing the frozen
+// value itself -- Groups and Extra each return a fresh copy -- so a caller that appends to or
+// otherwise mutates what it gets back can't corrupt the cached original or any other caller's
+// view of it, the way sharing a *UserInfo directly would allow.
+type FrozenUserInfo struct {
+	username string
+	uid      string
+	groups   []string
+	extra    map[string]ExtraValue
+}
+
+// Freeze returns a FrozenUserInfo holding a deep copy of m, so later mutation of m, m.Groups, or
+// m.Extra has no effect on the returned value.
+func Freeze(m *UserInfo) *FrozenUserInfo {
+	return &FrozenUserInfo{
+		username: m.Username,
+		uid:      m.UID,
+		groups:   append([]string(nil), m.Groups...),
+		extra:    copyExtra(m.Extra),
+	}
+}
+
+// Username returns the frozen Username.
+func (f *FrozenUserInfo) Username() string {
+	return f.username
+}
+
+// UID returns the frozen UID.
+func (f *FrozenUserInfo) UID() string {
+	return f.uid
+}
+
+// Groups returns a copy of the frozen Groups slice. Mutating the returned slice has no effect on
+// f or on any other caller's copy.
+func (f *FrozenUserInfo) Groups() []string {
+	return append([]string(nil), f.groups...)
+}
+
+// Extra returns a copy of the frozen Extra map, with each value slice itself copied. Mutating the
+// returned map, or any of its value slices, has no effect on f or on any other caller's copy.
+func (f *FrozenUserInfo) Extra() map[string]ExtraValue {
+	return copyExtra(f.extra)
+}
+
+// UserInfo returns a new *UserInfo carrying a copy of f's fields, safe for the caller to mutate
+// freely without affecting f.
+func (f *FrozenUserInfo) UserInfo() *UserInfo {
+	return &UserInfo{
+		Username: f.username,
+		UID:      f.uid,
+		Groups:   f.Groups(),
+		Extra:    f.Extra(),
+	}
+}
+
+// copyExtra returns a deep copy of extra, or nil if extra is nil.
+func copyExtra(extra map[string]ExtraValue) map[string]ExtraValue {
+	if extra == nil {
+		return nil
+	}
+	copied := make(map[string]ExtraValue, len(extra))
+	for key, value := range extra {
+		copied[key] = append(ExtraValue(nil), value...)
+	}
+	return copied
+}
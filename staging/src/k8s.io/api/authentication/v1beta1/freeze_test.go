@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFreezeGroupsCannotBeMutatedThroughFrozenView(t *testing.T) {
+	original := &UserInfo{
+		Username: "alice",
+		UID:      "1",
+		Groups:   []string{"admins", "developers"},
+		Extra: map[string]ExtraValue{
+			"scopes": {"read", "write"},
+		},
+	}
+	frozen := Freeze(original)
+
+	groups := frozen.Groups()
+	groups[0] = "mutated"
+	groups = append(groups, "extra-group")
+
+	if got := frozen.Groups(); !reflect.DeepEqual(got, []string{"admins", "developers"}) {
+		t.Errorf("mutating a returned Groups slice affected the frozen view, got %v", got)
+	}
+
+	extra := frozen.Extra()
+	extra["scopes"][0] = "mutated"
+	extra["new-key"] = ExtraValue{"sneaked-in"}
+
+	want := map[string]ExtraValue{"scopes": {"read", "write"}}
+	if got := frozen.Extra(); !reflect.DeepEqual(got, want) {
+		t.Errorf("mutating a returned Extra map affected the frozen view, got %v", got)
+	}
+
+	// mutating the original UserInfo after freezing also shouldn't reach the frozen view.
+	original.Groups[0] = "mutated-original"
+	original.Extra["scopes"][0] = "mutated-original"
+	if got := frozen.Groups(); !reflect.DeepEqual(got, []string{"admins", "developers"}) {
+		t.Errorf("mutating the source UserInfo after Freeze affected the frozen view, got %v", got)
+	}
+	if got := frozen.Extra(); !reflect.DeepEqual(got, want) {
+		t.Errorf("mutating the source UserInfo's Extra after Freeze affected the frozen view, got %v", got)
+	}
+}
+
+func TestFreezeUserInfoRoundTrip(t *testing.T) {
+	original := &UserInfo{
+		Username: "bob",
+		UID:      "2",
+		Groups:   []string{"viewers"},
+	}
+	frozen := Freeze(original)
+
+	if got := frozen.Username(); got != "bob" {
+		t.Errorf("expected Username %q, got %q", "bob", got)
+	}
+	if got := frozen.UID(); got != "2" {
+		t.Errorf("expected UID %q, got %q", "2", got)
+	}
+
+	roundTripped := frozen.UserInfo()
+	if !reflect.DeepEqual(original, roundTripped) {
+		t.Errorf("expected UserInfo() to reproduce the original, got %#v, want %#v", roundTripped, original)
+	}
+
+	roundTripped.Groups[0] = "mutated"
+	if got := frozen.Groups(); !reflect.DeepEqual(got, []string{"viewers"}) {
+		t.Errorf("mutating a UserInfo() result affected the frozen view, got %v", got)
+	}
+}
+
+func TestFreezeNilExtra(t *testing.T) {
+	frozen := Freeze(&UserInfo{Username: "carol"})
+	if got := frozen.Extra(); got != nil {
+		t.Errorf("expected a nil Extra map to stay nil after Freeze, got %v", got)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"bytes"
+	"testing"
+)
+
+// goldenUserInfo and goldenTokenReview are fixed, fully-populated fixtures whose marshaled form
+// is pinned below. A change to either golden byte slice means generated.pb.go's wire format
+// drifted -- intentionally or not -- and callers persisting these bytes (caches, etcd, webhook
+// payloads) need to know about it.
+
+func goldenUserInfo() UserInfo {
+	return UserInfo{
+		Username: "alice",
+		UID:      "12345",
+		Groups:   []string{"system:authenticated", "developers"},
+		Extra: map[string]ExtraValue{
+			"scopes": {"read", "write"},
+		},
+	}
+}
+
+var goldenUserInfoBytes = []byte{
+	0x0a, 0x05, 0x61, 0x6c, 0x69, 0x63, 0x65, 0x12, 0x05, 0x31, 0x32, 0x33,
+	0x34, 0x35, 0x1a, 0x14, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x3a, 0x61,
+	0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64,
+	0x1a, 0x0a, 0x64, 0x65, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x72, 0x73,
+	0x22, 0x17, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x0d,
+	0x0a, 0x04, 0x72, 0x65, 0x61, 0x64, 0x0a, 0x05, 0x77, 0x72, 0x69, 0x74,
+	0x65,
+}
+
+func goldenTokenReview() TokenReview {
+	return TokenReview{
+		Spec: TokenReviewSpec{Token: "abc123"},
+		Status: TokenReviewStatus{
+			Authenticated: true,
+			User:          goldenUserInfo(),
+		},
+	}
+}
+
+var goldenTokenReviewBytes = []byte{
+	0x0a, 0x12, 0x0a, 0x00, 0x12, 0x00, 0x1a, 0x00, 0x22, 0x00, 0x2a, 0x00,
+	0x32, 0x00, 0x38, 0x00, 0x42, 0x00, 0x7a, 0x00, 0x12, 0x08, 0x0a, 0x06,
+	0x61, 0x62, 0x63, 0x31, 0x32, 0x33, 0x1a, 0x4f, 0x08, 0x01, 0x12, 0x49,
+	0x0a, 0x05, 0x61, 0x6c, 0x69, 0x63, 0x65, 0x12, 0x05, 0x31, 0x32, 0x33,
+	0x34, 0x35, 0x1a, 0x14, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x3a, 0x61,
+	0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64,
+	0x1a, 0x0a, 0x64, 0x65, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x72, 0x73,
+	0x22, 0x17, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x0d,
+	0x0a, 0x04, 0x72, 0x65, 0x61, 0x64, 0x0a, 0x05, 0x77, 0x72, 0x69, 0x74,
+	0x65, 0x1a, 0x00,
+}
+
+// TestUserInfoWireFormatIsStable pins UserInfo's marshaled bytes against a recorded golden. If
+// this fails, something in generated.pb.go changed the on-wire encoding -- confirm that's
+// intentional (and that every persisted UserInfo can still be decoded) before updating the
+// golden.
+func TestUserInfoWireFormatIsStable(t *testing.T) {
+	info := goldenUserInfo()
+	got, err := info.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	if !bytes.Equal(got, goldenUserInfoBytes) {
+		t.Errorf("UserInfo wire format changed:\ngot:    %#v\nwanted: %#v", got, goldenUserInfoBytes)
+	}
+
+	var roundTripped UserInfo
+	if err := roundTripped.Unmarshal(goldenUserInfoBytes); err != nil {
+		t.Fatalf("unexpected error unmarshaling the golden bytes: %v", err)
+	}
+}
+
+// TestTokenReviewWireFormatIsStable is TestUserInfoWireFormatIsStable for TokenReview, covering a
+// fully-populated nested message (TokenReviewStatus.User) in addition to the top-level fields.
+func TestTokenReviewWireFormatIsStable(t *testing.T) {
+	review := goldenTokenReview()
+	got, err := review.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	if !bytes.Equal(got, goldenTokenReviewBytes) {
+		t.Errorf("TokenReview wire format changed:\ngot:    %#v\nwanted: %#v", got, goldenTokenReviewBytes)
+	}
+
+	var roundTripped TokenReview
+	if err := roundTripped.Unmarshal(goldenTokenReviewBytes); err != nil {
+		t.Fatalf("unexpected error unmarshaling the golden bytes: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// hashFieldSeparator and hashValueSeparator delimit fields and repeated values within a field
+// when canonicalizing a UserInfo for Hash. Every string is strconv.Quote'd before it's written,
+// which escapes control characters, so neither separator can appear inside a quoted Username,
+// UID, group name, or Extra key/value, and there's no ambiguity between e.g. a group named
+// "a\x1fb" and two groups "a" and "b".
+const (
+	hashFieldSeparator = "\x00"
+	hashValueSeparator = "\x1f"
+)
+
+// Hash returns a stable, collision-resistant identifier for m, suitable as a token cache key.
+// It's computed over Username, UID, and the sorted contents of Groups and Extra, so two
+// UserInfos that differ only in map or slice ordering hash identically, while any difference in
+// identity produces a different hash.
+func (m *UserInfo) Hash() string {
+	h := sha256.New()
+
+	h.Write([]byte("username:" + strconv.Quote(m.Username)))
+	h.Write([]byte(hashFieldSeparator + "uid:" + strconv.Quote(m.UID)))
+
+	groups := make([]string, 0, len(m.Groups))
+	for _, group := range m.Groups {
+		groups = append(groups, strconv.Quote(group))
+	}
+	sort.Strings(groups)
+	h.Write([]byte(hashFieldSeparator + "groups:" + strings.Join(groups, hashValueSeparator)))
+
+	keys := make([]string, 0, len(m.Extra))
+	for key := range m.Extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		values := make([]string, 0, len(m.Extra[key]))
+		for _, value := range m.Extra[key] {
+			values = append(values, strconv.Quote(value))
+		}
+		sort.Strings(values)
+		h.Write([]byte(hashFieldSeparator + "extra:" + strconv.Quote(key) + "=" + strings.Join(values, hashValueSeparator)))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "testing"
+
+func TestUserInfoHashIgnoresOrdering(t *testing.T) {
+	a := UserInfo{
+		Username: "alice",
+		UID:      "12345",
+		Groups:   []string{"admins", "developers"},
+		Extra: map[string]ExtraValue{
+			"scopes": {"read", "write"},
+			"teams":  {"a", "b"},
+		},
+	}
+	b := UserInfo{
+		Username: "alice",
+		UID:      "12345",
+		Groups:   []string{"developers", "admins"},
+		Extra: map[string]ExtraValue{
+			"teams":  {"b", "a"},
+			"scopes": {"write", "read"},
+		},
+	}
+
+	if a.Hash() != b.Hash() {
+		t.Errorf("expected two UserInfos differing only in map/slice ordering to hash identically, got %q and %q", a.Hash(), b.Hash())
+	}
+}
+
+func TestUserInfoHashDiffersByIdentity(t *testing.T) {
+	base := UserInfo{Username: "alice", UID: "12345", Groups: []string{"developers"}}
+
+	tests := []struct {
+		name  string
+		other UserInfo
+	}{
+		{name: "different username", other: UserInfo{Username: "bob", UID: base.UID, Groups: base.Groups}},
+		{name: "different uid", other: UserInfo{Username: base.Username, UID: "67890", Groups: base.Groups}},
+		{name: "different groups", other: UserInfo{Username: base.Username, UID: base.UID, Groups: []string{"admins"}}},
+		{name: "extra group", other: UserInfo{Username: base.Username, UID: base.UID, Groups: []string{"developers", "admins"}}},
+		{name: "extra key", other: UserInfo{Username: base.Username, UID: base.UID, Groups: base.Groups, Extra: map[string]ExtraValue{"scopes": {"read"}}}},
+	}
+
+	for _, tc := range tests {
+		if base.Hash() == tc.other.Hash() {
+			t.Errorf("%v: expected a different hash from the base UserInfo, got the same %q", tc.name, base.Hash())
+		}
+	}
+}
+
+func TestUserInfoHashStable(t *testing.T) {
+	info := benchmarkUserInfo()
+	if info.Hash() != info.Hash() {
+		t.Errorf("expected Hash to be deterministic across repeated calls on the same value")
+	}
+}
@@ -0,0 +1,30 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// HasStatus reports whether m.Status has been populated by a server, as opposed to still being
+// its zero value: it's true when Authenticated is set, Error is non-empty, or any User field is
+// set. A genuine "unauthenticated, no error, no user" response carries nothing that tells it
+// apart from an unfilled status, so HasStatus reports false for it.
+func (m *TokenReview) HasStatus() bool {
+	return m.Status.Authenticated || m.Status.Error != "" || m.Status.User.isPopulated()
+}
+
+// isPopulated reports whether any field of u has been set.
+func (u *UserInfo) isPopulated() bool {
+	return u.Username != "" || u.UID != "" || len(u.Groups) > 0 || len(u.Extra) > 0
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "testing"
+
+func TestTokenReviewHasStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		review TokenReview
+		want   bool
+	}{
+		{
+			name:   "fresh zero-valued review",
+			review: TokenReview{},
+			want:   false,
+		},
+		{
+			name: "authenticated review",
+			review: TokenReview{
+				Status: TokenReviewStatus{
+					Authenticated: true,
+					User:          UserInfo{Username: "alice"},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "error-only review",
+			review: TokenReview{
+				Status: TokenReviewStatus{Error: "token lookup failed"},
+			},
+			want: true,
+		},
+		{
+			name: "unauthenticated with no error",
+			review: TokenReview{
+				Status: TokenReviewStatus{Authenticated: false},
+			},
+			want: false,
+		},
+	}
+
+	for _, tc := range tests {
+		if got := tc.review.HasStatus(); got != tc.want {
+			t.Errorf("%v: expected HasStatus()=%v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
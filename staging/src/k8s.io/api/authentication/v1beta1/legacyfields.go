@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "io"
+
+// remapTopLevelFieldNumbers returns a copy of dAtA with every top-level field tag whose field
+// number appears as a key in remap rewritten to use the corresponding value instead, leaving the
+// wire type and the field's value bytes untouched. It lets UnmarshalOptions.Unmarshal accept a
+// payload encoded by an old client that still uses a field number a later schema change has
+// since renumbered, without teaching the generated Unmarshal methods about the old numbering.
+func remapTopLevelFieldNumbers(dAtA []byte, remap map[int32]int32) ([]byte, error) {
+	out := make([]byte, 0, len(dAtA))
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return nil, ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return nil, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := wire & 0x7
+		skippy, err := skipGeneratedWithDepthLimit(dAtA[preIndex:], defaultMaxGroupDepth)
+		if err != nil {
+			return nil, err
+		}
+		if skippy < 0 {
+			return nil, ErrInvalidLengthGenerated
+		}
+		if remapped, ok := remap[fieldNum]; ok {
+			fieldNum = remapped
+		}
+		tag := uint64(fieldNum)<<3 | wireType
+		tagBuf := make([]byte, sovGenerated(tag))
+		encodeVarintGenerated(tagBuf, 0, tag)
+		out = append(out, tagBuf...)
+		out = append(out, dAtA[iNdEx:preIndex+skippy]...)
+		iNdEx = preIndex + skippy
+	}
+	return out, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "testing"
+
+// userInfoWithLegacyUIDFieldNumber returns the wire bytes for a UserInfo whose UID was encoded
+// under field number 5 instead of its current field number, 2, as if written by an old client
+// from before UID was renumbered.
+func userInfoWithLegacyUIDFieldNumber(t *testing.T, username, uid string) []byte {
+	t.Helper()
+	info := UserInfo{Username: username, UID: uid}
+	dAtA, err := info.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	remapped, err := remapTopLevelFieldNumbers(dAtA, map[int32]int32{2: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return remapped
+}
+
+func TestUnmarshalOptionsLegacyUserInfoFieldNumberWithoutRemap(t *testing.T) {
+	dAtA := userInfoWithLegacyUIDFieldNumber(t, "alice", "12345")
+
+	var got UserInfo
+	if err := (UnmarshalOptions{}).Unmarshal(&got, dAtA); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Username != "alice" || got.UID != "" {
+		t.Fatalf("expected the legacy-numbered UID to be silently skipped, got %+v", got)
+	}
+}
+
+func TestUnmarshalOptionsLegacyUserInfoFieldNumberWithRemap(t *testing.T) {
+	dAtA := userInfoWithLegacyUIDFieldNumber(t, "alice", "12345")
+
+	opts := UnmarshalOptions{LegacyUserInfoFieldNumbers: map[int32]int32{5: 2}}
+	var got UserInfo
+	if err := opts.Unmarshal(&got, dAtA); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Username != "alice" || got.UID != "12345" {
+		t.Fatalf("expected the legacy-numbered UID to be aliased to the current field, got %+v", got)
+	}
+}
+
+func TestUnmarshalOptionsLegacyUserInfoFieldNumbersIgnoredForOtherTypes(t *testing.T) {
+	spec := TokenReviewSpec{Token: "tok"}
+	dAtA, err := spec.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	opts := UnmarshalOptions{LegacyUserInfoFieldNumbers: map[int32]int32{1: 5}}
+	var got TokenReviewSpec
+	if err := opts.Unmarshal(&got, dAtA); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Token != "tok" {
+		t.Fatalf("expected LegacyUserInfoFieldNumbers to have no effect on TokenReviewSpec, got %+v", got)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "fmt"
+
+// UnmarshalLenient decodes dAtA into a UserInfo field by field, continuing past a corrupt field
+// instead of returning on the first decode error the way Unmarshal does. Each top-level field is
+// skipped using the same wire-type-generic logic as the generated Unmarshal, so a single
+// malformed field (wrong wire type for its field number, a corrupt Extra entry)
+// doesn't prevent the remaining, otherwise-valid fields from being decoded. It's meant for
+// diagnosing a payload with multiple independent corruptions at once, not for normal decoding --
+// callers that just want an error on the first problem should use Unmarshal. On a fully valid
+// payload, decoded matches what Unmarshal would produce and errs is empty.
+func UnmarshalLenient(dAtA []byte) (decoded *UserInfo, errs []error) {
+	decoded = &UserInfo{}
+	l := len(dAtA)
+	iNdEx := 0
+	fieldIndex := 0
+	for iNdEx < l {
+		start := iNdEx
+		n, err := skipGeneratedWithDepthLimit(dAtA[iNdEx:], defaultMaxGroupDepth)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field %d: %v", fieldIndex, err))
+			break
+		}
+		if n < 0 {
+			errs = append(errs, fmt.Errorf("field %d: %v", fieldIndex, ErrInvalidLengthGenerated))
+			break
+		}
+		iNdEx = start + n
+
+		var field UserInfo
+		if err := field.Unmarshal(dAtA[start:iNdEx]); err != nil {
+			errs = append(errs, fmt.Errorf("field %d: %v", fieldIndex, err))
+		} else {
+			if field.Username != "" {
+				decoded.Username = field.Username
+			}
+			if field.UID != "" {
+				decoded.UID = field.UID
+			}
+			decoded.Groups = append(decoded.Groups, field.Groups...)
+			for key, value := range field.Extra {
+				if decoded.Extra == nil {
+					decoded.Extra = map[string]ExtraValue{}
+				}
+				decoded.Extra[key] = value
+			}
+		}
+		fieldIndex++
+	}
+	return decoded, errs
+}
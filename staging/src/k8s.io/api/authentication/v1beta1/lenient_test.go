@@ -0,0 +1,88 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+)
+
+// protoLengthDelimitedField builds a standalone wire-type-2 (length-delimited) field entry:
+// tag byte followed by a one-byte length (payload must be under 128 bytes) and the payload.
+func protoLengthDelimitedField(fieldNum int, payload []byte) []byte {
+	tag := byte(fieldNum<<3) | 2
+	chunk := append([]byte{tag, byte(len(payload))}, payload...)
+	return chunk
+}
+
+// protoVarintField builds a standalone wire-type-0 (varint) field entry with a one-byte value,
+// used here to simulate a field declared with the wrong wire type for its field number.
+func protoVarintField(fieldNum int, value byte) []byte {
+	tag := byte(fieldNum<<3) | 0
+	return []byte{tag, value}
+}
+
+func TestUserInfoUnmarshalLenientHappyPath(t *testing.T) {
+	in := &UserInfo{
+		Username: "alice",
+		UID:      "1",
+		Groups:   []string{"admins"},
+		Extra:    map[string]ExtraValue{"scopes": {"read"}},
+	}
+	dAtA, err := in.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decoded, errs := UnmarshalLenient(dAtA)
+	if len(errs) != 0 {
+		t.Fatalf("expected no errors decoding a valid payload, got %v", errs)
+	}
+	if !reflect.DeepEqual(decoded, in) {
+		t.Errorf("expected %+v, got %+v", in, decoded)
+	}
+}
+
+func TestUserInfoUnmarshalLenientCollectsMultipleErrors(t *testing.T) {
+	// field 2 (UID) and field 4 (Extra) are both length-delimited (wire type 2) in the real
+	// wire format; encoding them as wire type 0 (varint) instead simulates two independent
+	// corruptions that Unmarshal would reject, while still being skippable so decoding can
+	// continue past them.
+	var dAtA []byte
+	dAtA = append(dAtA, protoLengthDelimitedField(1, []byte("alice"))...)
+	dAtA = append(dAtA, protoVarintField(2, 5)...)
+	dAtA = append(dAtA, protoLengthDelimitedField(3, []byte("admins"))...)
+	dAtA = append(dAtA, protoVarintField(4, 7)...)
+
+	decoded, errs := UnmarshalLenient(dAtA)
+	if len(errs) != 2 {
+		t.Fatalf("expected exactly 2 errors, got %d: %v", len(errs), errs)
+	}
+
+	if decoded.Username != "alice" {
+		t.Errorf("expected the good Username field to decode, got %q", decoded.Username)
+	}
+	if !reflect.DeepEqual(decoded.Groups, []string{"admins"}) {
+		t.Errorf("expected the good Groups field to decode, got %v", decoded.Groups)
+	}
+	if decoded.UID != "" {
+		t.Errorf("expected the corrupt UID field to be left unset, got %q", decoded.UID)
+	}
+	if len(decoded.Extra) != 0 {
+		t.Errorf("expected the corrupt Extra field to be left unset, got %v", decoded.Extra)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"io"
+)
+
+// ValidateUserInfoLengthPrefixes pre-scans dAtA, a serialized UserInfo, and checks that every
+// length-delimited field's declared length (Username, UID, each Groups entry, each Extra entry)
+// fits within the remaining buffer, without decoding or allocating anything. Unmarshal already
+// rejects an individual field whose declared length overruns the buffer, but only once it reaches
+// that field -- for a repeated field like Groups, that means every entry before the corrupt one
+// has already been decoded and appended to the slice. Calling this first lets a caller reject a
+// corrupt payload up front, before Unmarshal does any of that partial work.
+func ValidateUserInfoLengthPrefixes(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType != 2 {
+			n, err := skipGenerated(dAtA[preIndex:])
+			if err != nil {
+				return err
+			}
+			iNdEx = preIndex + n
+			continue
+		}
+
+		var length uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			length |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		intLength := int(length)
+		if intLength < 0 {
+			return ErrInvalidLengthGenerated
+		}
+		postIndex := iNdEx + intLength
+		if postIndex > l {
+			return fmt.Errorf("proto: UserInfo: field %d declares length %d at offset %d, which exceeds the remaining %d bytes", fieldNum, intLength, iNdEx, l-iNdEx)
+		}
+		iNdEx = postIndex
+	}
+	return nil
+}
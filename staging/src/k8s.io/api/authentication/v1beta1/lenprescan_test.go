@@ -0,0 +1,58 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "testing"
+
+func TestValidateUserInfoLengthPrefixesHappyPath(t *testing.T) {
+	in := &UserInfo{
+		Username: "alice",
+		UID:      "1",
+		Groups:   []string{"admins", "developers"},
+		Extra:    map[string]ExtraValue{"scopes": {"read"}},
+	}
+	dAtA, err := in.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := ValidateUserInfoLengthPrefixes(dAtA); err != nil {
+		t.Errorf("expected a valid payload to pass validation, got %v", err)
+	}
+}
+
+// TestValidateUserInfoLengthPrefixesCatchesOverrunBeforeDecoding checks that a Groups entry
+// claiming a length beyond the remaining buffer is rejected up front, for a payload where an
+// earlier Groups entry is still well-formed -- i.e. Unmarshal would have already appended it
+// before hitting the corrupt one.
+func TestValidateUserInfoLengthPrefixesCatchesOverrunBeforeDecoding(t *testing.T) {
+	in := &UserInfo{Groups: []string{"admins"}}
+	dAtA, err := in.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// append a second Groups entry (field 3, wire type 2) claiming a length far beyond
+	// what follows it in the buffer.
+	base := len(dAtA)
+	corrupt := append(dAtA, protoLengthDelimitedField(3, []byte("x"))...)
+	corrupt[base+1] = 0x7F // overwrite the declared length with a value the trailing payload can't satisfy
+
+	if err := ValidateUserInfoLengthPrefixes(corrupt); err == nil {
+		t.Errorf("expected an error for a Groups entry whose declared length exceeds the buffer")
+	}
+}
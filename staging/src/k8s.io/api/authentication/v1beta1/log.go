@@ -0,0 +1,42 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "fmt"
+
+// defaultUserInfoLogStringLimit bounds the output of UserInfo.BoundedString, since a
+// maliciously or accidentally huge Groups/Extra can otherwise flood logs.
+const defaultUserInfoLogStringLimit = 2048
+
+// BoundedString is like String, but when String's output is longer than maxLen bytes (or
+// defaultUserInfoLogStringLimit if maxLen <= 0) it keeps only the first maxLen bytes and appends
+// a "...(truncated, N bytes total)" marker, so the result can exceed maxLen by the length of that
+// marker. Truncation is by bytes, not by group or Extra entry. It's meant for logging call sites that
+// receive a UserInfo from an untrusted or unbounded source, such as a webhook authenticator.
+func (this *UserInfo) BoundedString(maxLen int) string {
+	if this == nil {
+		return "nil"
+	}
+	if maxLen <= 0 {
+		maxLen = defaultUserInfoLogStringLimit
+	}
+	s := this.String()
+	if len(s) <= maxLen {
+		return s
+	}
+	return fmt.Sprintf("%s...(truncated, %d bytes total)", s[:maxLen], len(s))
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUserInfoBoundedString(t *testing.T) {
+	groups := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		groups = append(groups, "a-fairly-long-group-name-to-pad-things-out")
+	}
+	u := &UserInfo{Username: "alice", Groups: groups}
+
+	full := u.String()
+	if len(full) < 10000 {
+		t.Fatalf("expected a large unbounded String() for this fixture, got %d bytes", len(full))
+	}
+
+	bounded := u.BoundedString(100)
+	if len(bounded) > 200 {
+		t.Errorf("expected BoundedString(100) to stay small, got %d bytes", len(bounded))
+	}
+	if !strings.HasSuffix(bounded, "bytes total)") && !strings.Contains(bounded, "truncated") {
+		t.Errorf("expected truncation marker in output, got %q", bounded)
+	}
+
+	small := &UserInfo{Username: "bob"}
+	if got, want := small.BoundedString(100), small.String(); got != want {
+		t.Errorf("expected untruncated output for small UserInfo, got %q want %q", got, want)
+	}
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// MarshalAppend marshals m like Marshal, but appends the encoded bytes to buf instead of
+// allocating a fresh slice, growing buf as needed and returning the result. This mirrors
+// strconv.AppendInt-style ergonomics so callers can reuse a pooled buffer across many messages
+// instead of allocating one per call.
+func (m *UserInfo) MarshalAppend(buf []byte) ([]byte, error) {
+	size := m.Size()
+	offset := len(buf)
+
+	if cap(buf)-offset < size {
+		grown := make([]byte, offset, offset+size)
+		copy(grown, buf)
+		buf = grown
+	}
+	buf = buf[:offset+size]
+
+	if _, err := m.MarshalTo(buf[offset:]); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUserInfoMarshalAppendTwoMessages(t *testing.T) {
+	first := UserInfo{Username: "alice", Groups: []string{"dev"}}
+	second := UserInfo{Username: "bob", UID: "67890", Groups: []string{"ops", "admins"}}
+	firstSize := first.Size()
+
+	var buf []byte
+	buf, err := first.MarshalAppend(buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf, err = second.MarshalAppend(buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(buf) != firstSize+second.Size() {
+		t.Fatalf("expected %d total bytes, got %d", firstSize+second.Size(), len(buf))
+	}
+
+	var gotFirst, gotSecond UserInfo
+	if err := gotFirst.Unmarshal(buf[:firstSize]); err != nil {
+		t.Fatalf("unexpected error decoding first message: %v", err)
+	}
+	if err := gotSecond.Unmarshal(buf[firstSize:]); err != nil {
+		t.Fatalf("unexpected error decoding second message: %v", err)
+	}
+	if !reflect.DeepEqual(first, gotFirst) {
+		t.Errorf("expected first %+v, got %+v", first, gotFirst)
+	}
+	if !reflect.DeepEqual(second, gotSecond) {
+		t.Errorf("expected second %+v, got %+v", second, gotSecond)
+	}
+}
+
+func TestUserInfoMarshalAppendPreservesExistingPrefix(t *testing.T) {
+	info := UserInfo{Username: "alice"}
+	prefix := []byte("prefix:")
+
+	buf, err := info.MarshalAppend(append([]byte(nil), prefix...))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(buf[:len(prefix)]) != string(prefix) {
+		t.Errorf("expected the existing prefix to be preserved, got %q", buf[:len(prefix)])
+	}
+
+	var got UserInfo
+	if err := got.Unmarshal(buf[len(prefix):]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(info, got) {
+		t.Errorf("expected %+v, got %+v", info, got)
+	}
+}
+
+func BenchmarkUserInfoMarshalAppend(b *testing.B) {
+	info := benchmarkUserInfo()
+	buf := make([]byte, 0, info.Size())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		buf, err = info.MarshalAppend(buf[:0])
+		if err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "fmt"
+
+// ErrMarshalSizeExceeded is returned by UserInfo.MarshalBounded when the encoded size of m would
+// exceed the configured limit.
+type ErrMarshalSizeExceeded struct {
+	Size    int
+	MaxSize int
+}
+
+func (e *ErrMarshalSizeExceeded) Error() string {
+	return fmt.Sprintf("marshaled size %d exceeds limit of %d bytes", e.Size, e.MaxSize)
+}
+
+// MarshalBounded marshals m like Marshal, but checks Size() against maxBytes first and returns
+// ErrMarshalSizeExceeded without allocating the encode buffer when it's too large. This protects
+// a caller relaying someone else's UserInfo -- for example the aggregator forwarding a webhook's
+// TokenReview -- from a pathologically large value, such as a huge Groups list, blowing memory on
+// the allocation itself.
+func (m *UserInfo) MarshalBounded(maxBytes int) ([]byte, error) {
+	if size := m.Size(); size > maxBytes {
+		return nil, &ErrMarshalSizeExceeded{Size: size, MaxSize: maxBytes}
+	}
+	return m.Marshal()
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "testing"
+
+func TestUserInfoMarshalBoundedAtBoundary(t *testing.T) {
+	info := UserInfo{Username: "alice", Groups: []string{"dev", "ops"}}
+	size := info.Size()
+
+	dAtA, err := info.MarshalBounded(size)
+	if err != nil {
+		t.Fatalf("expected a UserInfo exactly at the limit to marshal successfully, got: %v", err)
+	}
+	if len(dAtA) != size {
+		t.Errorf("expected %d marshaled bytes, got %d", size, len(dAtA))
+	}
+
+	if _, err := info.MarshalBounded(size - 1); err == nil {
+		t.Fatalf("expected a UserInfo one byte over the limit to be rejected")
+	} else if sizeErr, ok := err.(*ErrMarshalSizeExceeded); !ok {
+		t.Errorf("expected *ErrMarshalSizeExceeded, got %T: %v", err, err)
+	} else if sizeErr.Size != size || sizeErr.MaxSize != size-1 {
+		t.Errorf("expected Size=%d MaxSize=%d, got Size=%d MaxSize=%d", size, size-1, sizeErr.Size, sizeErr.MaxSize)
+	}
+}
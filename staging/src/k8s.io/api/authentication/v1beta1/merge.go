@@ -0,0 +1,64 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// Merge combines other into m in place, for chained authenticators that need to union two
+// partial identities into one: Username and UID keep m's value if it's already set, falling
+// back to other's otherwise; Groups becomes the de-duplicated union of both, in first-seen
+// order (m's groups first); and Extra is combined key by key, concatenating other's values
+// after m's for any key both share. Doing nothing when other is nil lets callers merge
+// unconditionally without a nil check at every call site.
+func (m *UserInfo) Merge(other *UserInfo) {
+	if other == nil {
+		return
+	}
+
+	if m.Username == "" {
+		m.Username = other.Username
+	}
+	if m.UID == "" {
+		m.UID = other.UID
+	}
+
+	m.Groups = mergeGroups(m.Groups, other.Groups)
+
+	for key, values := range other.Extra {
+		if m.Extra == nil {
+			m.Extra = map[string]ExtraValue{}
+		}
+		merged := make(ExtraValue, 0, len(m.Extra[key])+len(values))
+		merged = append(merged, m.Extra[key]...)
+		merged = append(merged, values...)
+		m.Extra[key] = merged
+	}
+}
+
+// mergeGroups returns the de-duplicated union of the given group slices, in first-seen order.
+func mergeGroups(groupSlices ...[]string) []string {
+	seen := map[string]bool{}
+	var merged []string
+	for _, groups := range groupSlices {
+		for _, group := range groups {
+			if seen[group] {
+				continue
+			}
+			seen[group] = true
+			merged = append(merged, group)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUserInfoMergeOverlappingGroups(t *testing.T) {
+	m := &UserInfo{Groups: []string{"dev", "admin"}}
+	other := &UserInfo{Groups: []string{"admin", "ops"}}
+
+	m.Merge(other)
+
+	want := []string{"dev", "admin", "ops"}
+	if !reflect.DeepEqual(m.Groups, want) {
+		t.Errorf("expected de-duplicated groups %v, got %v", want, m.Groups)
+	}
+}
+
+func TestUserInfoMergeConflictingUsername(t *testing.T) {
+	m := &UserInfo{Username: "alice", UID: "1"}
+	other := &UserInfo{Username: "bob", UID: "2"}
+
+	m.Merge(other)
+
+	if m.Username != "alice" {
+		t.Errorf("expected receiver's non-empty Username to win, got %q", m.Username)
+	}
+	if m.UID != "1" {
+		t.Errorf("expected receiver's non-empty UID to win, got %q", m.UID)
+	}
+
+	var empty UserInfo
+	empty.Merge(other)
+	if empty.Username != "bob" || empty.UID != "2" {
+		t.Errorf("expected other's values to fill in an empty receiver, got Username=%q UID=%q", empty.Username, empty.UID)
+	}
+}
+
+func TestUserInfoMergeDisjointExtras(t *testing.T) {
+	m := &UserInfo{Extra: map[string]ExtraValue{"scopes": {"read"}}}
+	other := &UserInfo{Extra: map[string]ExtraValue{"roles": {"admin"}}}
+
+	m.Merge(other)
+
+	want := map[string]ExtraValue{
+		"scopes": {"read"},
+		"roles":  {"admin"},
+	}
+	if !reflect.DeepEqual(m.Extra, want) {
+		t.Errorf("expected %v, got %v", want, m.Extra)
+	}
+}
+
+func TestUserInfoMergeOverlappingExtraKeys(t *testing.T) {
+	m := &UserInfo{Extra: map[string]ExtraValue{"scopes": {"read"}}}
+	other := &UserInfo{Extra: map[string]ExtraValue{"scopes": {"write"}}}
+
+	m.Merge(other)
+
+	want := ExtraValue{"read", "write"}
+	if !reflect.DeepEqual(m.Extra["scopes"], want) {
+		t.Errorf("expected concatenated extra values %v, got %v", want, m.Extra["scopes"])
+	}
+}
+
+func TestUserInfoMergeNilOther(t *testing.T) {
+	m := &UserInfo{Username: "alice", Groups: []string{"dev"}}
+	m.Merge(nil)
+
+	if m.Username != "alice" || !reflect.DeepEqual(m.Groups, []string{"dev"}) {
+		t.Errorf("expected merging nil to be a no-op, got %+v", m)
+	}
+}
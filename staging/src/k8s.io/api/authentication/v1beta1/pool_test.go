@@ -0,0 +1,111 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestDecodeFromPoolRoundTrip(t *testing.T) {
+	want := benchmarkUserInfo()
+	dAtA, err := want.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, release := DecodeFromPool(dAtA)
+	defer release()
+	if got == nil {
+		t.Fatalf("expected a decoded UserInfo, got nil")
+	}
+	if !reflect.DeepEqual(want, *got) {
+		t.Errorf("expected %+v, got %+v", want, *got)
+	}
+}
+
+func TestDecodeFromPoolMalformed(t *testing.T) {
+	got, release := DecodeFromPool([]byte{0xff, 0xff, 0xff})
+	defer release()
+	if got != nil {
+		t.Errorf("expected a nil UserInfo for malformed data, got %+v", got)
+	}
+}
+
+func TestDecodeFromPoolClearsPreviousContents(t *testing.T) {
+	dirty := &UserInfo{
+		Username: "stale",
+		Groups:   []string{"stale-group"},
+		Extra:    map[string]ExtraValue{"stale": {"value"}},
+	}
+	UserInfoPool.Put(dirty)
+
+	want := UserInfo{Username: "alice"}
+	dAtA, err := want.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, release := DecodeFromPool(dAtA)
+	defer release()
+	if got.Username != "alice" || len(got.Groups) != 0 || len(got.Extra) != 0 {
+		t.Errorf("expected a clean decode, got %+v", got)
+	}
+}
+
+// TestDecodeFromPoolRace exercises DecodeFromPool from many goroutines concurrently. Run with
+// -race to catch any sharing of pooled UserInfo state across decodes.
+func TestDecodeFromPoolRace(t *testing.T) {
+	info := benchmarkUserInfo()
+	dAtA, err := info.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				info, release := DecodeFromPool(dAtA)
+				if info.Username != "alice" {
+					t.Errorf("expected Username %q, got %q", "alice", info.Username)
+				}
+				release()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkUserInfoDecodeFromPool(b *testing.B) {
+	benchmarkInfo := benchmarkUserInfo()
+	dAtA, err := benchmarkInfo.Marshal()
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		info, release := DecodeFromPool(dAtA)
+		if info == nil {
+			b.Fatalf("unexpected decode failure")
+		}
+		release()
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "io"
+
+// UnmarshalPresized decodes dAtA into a UserInfo the same way Unmarshal does, except it first
+// scans dAtA once to count Groups (field 3) occurrences and preallocates the Groups slice to that
+// exact capacity, avoiding the repeated slice growth and copying that Unmarshal's append-per-
+// element loop incurs for a UserInfo with a large Groups list. The decoded result is identical to
+// what Unmarshal(dAtA) would produce; only the allocation pattern differs, so this is only worth
+// reaching for when Groups is known to be large.
+func UnmarshalPresized(dAtA []byte) (*UserInfo, error) {
+	groupCount, err := countUserInfoGroups(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	m := &UserInfo{}
+	if groupCount > 0 {
+		m.Groups = make([]string, 0, groupCount)
+	}
+	if err := m.Unmarshal(dAtA); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// countUserInfoGroups pre-scans dAtA, a serialized UserInfo, counting how many top-level Groups
+// (field 3) entries it contains, without decoding or allocating any of them.
+func countUserInfoGroups(dAtA []byte) (int, error) {
+	l := len(dAtA)
+	iNdEx := 0
+	count := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType != 2 {
+			n, err := skipGenerated(dAtA[preIndex:])
+			if err != nil {
+				return 0, err
+			}
+			iNdEx = preIndex + n
+			continue
+		}
+
+		var length uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			length |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		intLength := int(length)
+		if intLength < 0 {
+			return 0, ErrInvalidLengthGenerated
+		}
+		postIndex := iNdEx + intLength
+		if postIndex > l {
+			return 0, io.ErrUnexpectedEOF
+		}
+		if fieldNum == 3 {
+			count++
+		}
+		iNdEx = postIndex
+	}
+	return count, nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func userInfoWithGroups(n int) *UserInfo {
+	groups := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		groups = append(groups, fmt.Sprintf("group-%d", i))
+	}
+	return &UserInfo{
+		Username: "alice",
+		UID:      "1",
+		Groups:   groups,
+		Extra: map[string]ExtraValue{
+			"scopes": {"read", "write"},
+		},
+	}
+}
+
+func TestUnmarshalPresizedMatchesUnmarshal(t *testing.T) {
+	in := userInfoWithGroups(500)
+	dAtA, err := in.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+
+	want := &UserInfo{}
+	if err := want.Unmarshal(dAtA); err != nil {
+		t.Fatalf("unexpected error calling Unmarshal: %v", err)
+	}
+
+	got, err := UnmarshalPresized(dAtA)
+	if err != nil {
+		t.Fatalf("unexpected error calling UnmarshalPresized: %v", err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("UnmarshalPresized produced a different result than Unmarshal:\nwant: %#v\ngot:  %#v", want, got)
+	}
+	if cap(got.Groups) != len(in.Groups) {
+		t.Errorf("expected Groups to be preallocated to exactly %d, got cap %d", len(in.Groups), cap(got.Groups))
+	}
+}
+
+func TestUnmarshalPresizedNoGroups(t *testing.T) {
+	in := &UserInfo{Username: "bob"}
+	dAtA, err := in.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+
+	got, err := UnmarshalPresized(dAtA)
+	if err != nil {
+		t.Fatalf("unexpected error calling UnmarshalPresized: %v", err)
+	}
+	if got.Username != "bob" || len(got.Groups) != 0 {
+		t.Errorf("unexpected result: %#v", got)
+	}
+}
+
+func BenchmarkUnmarshalGroups(b *testing.B) {
+	in := userInfoWithGroups(500)
+	dAtA, err := in.Marshal()
+	if err != nil {
+		b.Fatalf("unexpected error marshaling: %v", err)
+	}
+
+	b.Run("Unmarshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			out := &UserInfo{}
+			if err := out.Unmarshal(dAtA); err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+		}
+	})
+
+	b.Run("UnmarshalPresized", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := UnmarshalPresized(dAtA); err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// ReadFromFramed decodes a single length-delimited TokenReview message from r without requiring the
+// whole message to already be buffered in memory: a 4-byte big-endian uint32 giving the body's
+// length, followed by that many bytes of the TokenReview's wire format -- the same framing used
+// by k8s.io/apimachinery/pkg/util/framer's length-delimited frame reader. maxSize caps the body
+// length accepted, so a corrupt or hostile length prefix can't make ReadFromFramed allocate an
+// enormous buffer before it even knows whether the frame is well-formed.
+func (m *TokenReview) ReadFromFramed(r io.Reader, maxSize int64) error {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return err
+	}
+
+	size := int64(binary.BigEndian.Uint32(header[:]))
+	if size > maxSize {
+		return fmt.Errorf("framed TokenReview of %d bytes exceeds the limit of %d bytes", size, maxSize)
+	}
+
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return err
+	}
+	return m.Unmarshal(body)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// frameTokenReview encodes review the way ReadFromFramed expects to read it: a 4-byte big-endian
+// length prefix followed by the marshaled body.
+func frameTokenReview(t *testing.T, review TokenReview) []byte {
+	t.Helper()
+	body, err := review.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(body)))
+	return append(header[:], body...)
+}
+
+func TestTokenReviewReadFromValid(t *testing.T) {
+	want := benchmarkTokenReview()
+	framed := frameTokenReview(t, want)
+
+	var got TokenReview
+	if err := got.ReadFromFramed(bytes.NewReader(framed), int64(len(framed))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestTokenReviewReadFromTruncated(t *testing.T) {
+	framed := frameTokenReview(t, benchmarkTokenReview())
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "truncated header", data: framed[:2]},
+		{name: "truncated body", data: framed[:len(framed)-1]},
+	}
+	for _, tc := range tests {
+		var review TokenReview
+		err := review.ReadFromFramed(bytes.NewReader(tc.data), int64(len(framed)))
+		if err == nil {
+			t.Errorf("%v: expected an error decoding a truncated stream", tc.name)
+		}
+		if err != io.ErrUnexpectedEOF && err != io.EOF {
+			t.Errorf("%v: expected io.ErrUnexpectedEOF or io.EOF, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestTokenReviewReadFromOversized(t *testing.T) {
+	framed := frameTokenReview(t, benchmarkTokenReview())
+
+	var review TokenReview
+	err := review.ReadFromFramed(bytes.NewReader(framed), int64(len(framed))-5)
+	if err == nil {
+		t.Fatalf("expected a frame over maxSize to be rejected")
+	}
+}
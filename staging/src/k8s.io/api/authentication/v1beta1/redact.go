@@ -0,0 +1,39 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "fmt"
+
+// redactedTokenPrefixLen is how many leading bytes of a non-empty token are included in its
+// redacted representation, purely to help correlate log lines with a particular token without
+// ever logging enough of it to be useful to an attacker.
+const redactedTokenPrefixLen = 4
+
+// redactedTokenString returns a placeholder for token suitable for logging: callers such as
+// TokenReviewSpec.String() embed raw field values via fmt.Sprintf("%v", ...), which would
+// otherwise leak the bearer token itself into logs. An empty token still prints as empty, since
+// there's nothing there to redact and "no token" is itself useful debugging information.
+func redactedTokenString(token string) string {
+	if token == "" {
+		return ""
+	}
+	prefix := token
+	if len(prefix) > redactedTokenPrefixLen {
+		prefix = prefix[:redactedTokenPrefixLen]
+	}
+	return fmt.Sprintf("<redacted, len=%d, prefix=%s...>", len(token), prefix)
+}
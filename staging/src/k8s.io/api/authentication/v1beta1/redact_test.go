@@ -0,0 +1,56 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenReviewSpecStringRedactsToken(t *testing.T) {
+	spec := &TokenReviewSpec{Token: "super-secret-bearer-token"}
+
+	s := spec.String()
+
+	if strings.Contains(s, spec.Token) {
+		t.Fatalf("String() leaked the raw token: %q", s)
+	}
+	if !strings.Contains(s, "<redacted") {
+		t.Errorf("expected String() to contain a redaction placeholder, got %q", s)
+	}
+}
+
+func TestTokenReviewSpecStringEmptyToken(t *testing.T) {
+	spec := &TokenReviewSpec{Token: ""}
+
+	if s := spec.String(); strings.Contains(s, "<redacted") {
+		t.Errorf("expected no redaction placeholder for an empty token, got %q", s)
+	}
+}
+
+func TestRedactedTokenStringPreservesLength(t *testing.T) {
+	token := "abcdefghijklmnop"
+
+	got := redactedTokenString(token)
+
+	if strings.Contains(got, token) {
+		t.Fatalf("redactedTokenString leaked the raw token: %q", got)
+	}
+	if !strings.Contains(got, "len=16") {
+		t.Errorf("expected redacted string to report the token length, got %q", got)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+)
+
+// protoMessage is satisfied by every generated type in this package that has hand-rolled
+// Marshal/Unmarshal methods, which lets assertProtoRoundTrip work across all of them.
+type protoMessage interface {
+	Marshal() ([]byte, error)
+}
+
+// assertProtoRoundTrip marshals in, unmarshals the bytes into a freshly allocated *T via
+// unmarshal, and fails the test unless the result deep-equals in. It's meant to be reused by
+// every generated-proto type in this package instead of duplicating the marshal/unmarshal/
+// compare boilerplate per type.
+func assertProtoRoundTrip(t *testing.T, in protoMessage, out interface{}, unmarshal func([]byte) error) {
+	t.Helper()
+	dAtA, err := in.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error marshaling %#v: %v", in, err)
+	}
+	if err := unmarshal(dAtA); err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round-trip mismatch:\nin:  %#v\nout: %#v", in, out)
+	}
+}
+
+func TestUserInfoProtoRoundTrip(t *testing.T) {
+	in := &UserInfo{
+		Username: "alice",
+		UID:      "123",
+		Groups:   []string{"admins", "developers"},
+		Extra:    map[string]ExtraValue{"k": {"v1", "v2"}},
+	}
+	out := &UserInfo{}
+	assertProtoRoundTrip(t, in, out, out.Unmarshal)
+}
+
+func TestTokenReviewProtoRoundTrip(t *testing.T) {
+	in := &TokenReview{
+		Spec: TokenReviewSpec{Token: "abc"},
+		Status: TokenReviewStatus{
+			Authenticated: true,
+			User:          UserInfo{Username: "alice"},
+		},
+	}
+	out := &TokenReview{}
+	assertProtoRoundTrip(t, in, out, out.Unmarshal)
+}
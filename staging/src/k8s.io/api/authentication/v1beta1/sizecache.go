@@ -0,0 +1,62 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "sync"
+
+// SizeCachedTokenReview wraps a TokenReview that the caller promises not to mutate after
+// construction, and memoizes the result of Size(). TokenReview.Size() walks the embedded
+// ObjectMeta, Spec, and Status on every call; for a TokenReview that is marshaled more than
+// once (for example, once to send to a webhook and once to log), this avoids recomputing the
+// same size repeatedly.
+type SizeCachedTokenReview struct {
+	tokenReview TokenReview
+
+	sizeOnce sync.Once
+	size     int
+}
+
+// NewSizeCachedTokenReview returns a SizeCachedTokenReview wrapping tokenReview. tokenReview
+// must not be mutated after being passed in; doing so invalidates the cache without detection.
+func NewSizeCachedTokenReview(tokenReview TokenReview) *SizeCachedTokenReview {
+	return &SizeCachedTokenReview{tokenReview: tokenReview}
+}
+
+// TokenReview returns the wrapped TokenReview.
+func (c *SizeCachedTokenReview) TokenReview() TokenReview {
+	return c.tokenReview
+}
+
+// Size returns the marshaled size of the wrapped TokenReview, computing and caching it on the
+// first call.
+func (c *SizeCachedTokenReview) Size() int {
+	c.sizeOnce.Do(func() {
+		c.size = c.tokenReview.Size()
+	})
+	return c.size
+}
+
+// Marshal marshals the wrapped TokenReview, reusing the cached size to allocate the output
+// buffer instead of calling Size() again.
+func (c *SizeCachedTokenReview) Marshal() ([]byte, error) {
+	dAtA := make([]byte, c.Size())
+	n, err := c.tokenReview.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// defaultMaxGroupDepth bounds how deeply skipGeneratedWithDepthLimit will descend into nested
+// legacy proto2 "groups" (wire type 3) by default when UnmarshalOptions.MaxGroupDepth is left
+// at its zero value.
+const defaultMaxGroupDepth = 10000
+
+// errGroupDepthExceeded is returned by skipGeneratedWithDepthLimit when a payload nests groups
+// more deeply than the configured limit allows.
+var errGroupDepthExceeded = errors.New("proto: exceeded maximum group nesting depth")
+
+// skipGeneratedWithDepthLimit behaves like the generated skipGenerated (see generated.pb.go),
+// except that its handling of wire type 3 ("start group") tracks recursion depth and returns
+// errGroupDepthExceeded instead of recursing past maxDepth levels. The generated skipGenerated
+// recurses once per nested, unclosed group with no bound at all, so a crafted payload of deeply
+// nested groups can exhaust the goroutine stack; callers in this package that walk untrusted
+// bytes before handing them to the generated Unmarshal (such as countTopLevelField) should use
+// this instead. maxDepth <= 0 means unlimited, matching skipGenerated's behavior.
+func skipGeneratedWithDepthLimit(dAtA []byte, maxDepth int) (n int, err error) {
+	return skipGeneratedDepth(dAtA, maxDepth, 0)
+}
+
+// checkGroupDepthLimit walks dAtA's top-level protobuf field tags, without otherwise decoding
+// the payload, and returns errGroupDepthExceeded if skipping any of them would require
+// recursing more than maxDepth levels into nested groups.
+func checkGroupDepthLimit(dAtA []byte, maxDepth int) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		skippy, err := skipGeneratedWithDepthLimit(dAtA[iNdEx:], maxDepth)
+		if err != nil {
+			return err
+		}
+		if skippy < 0 {
+			return ErrInvalidLengthGenerated
+		}
+		iNdEx += skippy
+	}
+	return nil
+}
+
+func skipGeneratedDepth(dAtA []byte, maxDepth, depth int) (n int, err error) {
+	if maxDepth > 0 && depth > maxDepth {
+		return 0, errGroupDepthExceeded
+	}
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthGenerated
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipGeneratedDepth(dAtA[start:], maxDepth, depth+1)
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "testing"
+
+// deeplyNestedGroupPayload returns a field-1 wire-type-3 ("start group") tag, repeated depth
+// times with no matching "end group" tags and nothing after them. skipGenerated recurses once per start-group tag
+// it sees before it ever notices the groups are unclosed, so this is enough to drive the
+// recursion depth this test cares about.
+func deeplyNestedGroupPayload(depth int) []byte {
+	const startGroupTag = byte(1<<3 | 3) // field 1, wire type 3
+	payload := make([]byte, depth)
+	for i := range payload {
+		payload[i] = startGroupTag
+	}
+	return payload
+}
+
+func TestSkipGeneratedWithDepthLimitRejectsDeepNesting(t *testing.T) {
+	payload := deeplyNestedGroupPayload(10000)
+
+	if _, err := skipGeneratedWithDepthLimit(payload, 100); err != errGroupDepthExceeded {
+		t.Errorf("expected errGroupDepthExceeded for a payload nested well past the limit, got: %v", err)
+	}
+
+	if _, err := skipGeneratedWithDepthLimit(payload, 0); err == errGroupDepthExceeded {
+		t.Errorf("expected maxDepth <= 0 to mean unlimited, but the depth guard still fired")
+	}
+}
+
+func TestUnmarshalOptionsMaxGroupDepth(t *testing.T) {
+	payload := deeplyNestedGroupPayload(10000)
+
+	opts := UnmarshalOptions{MaxGroupDepth: 100}
+	var tokenReview TokenReview
+	if err := opts.Unmarshal(&tokenReview, payload); err != errGroupDepthExceeded {
+		t.Errorf("expected MaxGroupDepth to reject a deeply nested payload with errGroupDepthExceeded, got: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"io"
+)
+
+// tokenReviewFields, tokenReviewSpecFields, tokenReviewStatusFields, and userInfoFields list the
+// field numbers of each message's known wire format; UnmarshalStrict rejects any other. Lenient decoding (via the generated Unmarshal methods) always skips unknown fields, which
+// can silently mask schema drift between an old client and a newer server. The UnmarshalStrict
+// variants below perform an extra, allocation-free pass over the wire bytes to catch that case.
+var tokenReviewFields = map[int32]bool{1: true, 2: true, 3: true}
+var tokenReviewSpecFields = map[int32]bool{1: true}
+var tokenReviewStatusFields = map[int32]bool{1: true, 2: true, 3: true}
+var userInfoFields = map[int32]bool{1: true, 2: true, 3: true, 4: true}
+
+// checkKnownFields walks dAtA's top-level protobuf field tags, without otherwise decoding
+// the payload, and returns an error the first time it finds a field number not in known.
+func checkKnownFields(dAtA []byte, known map[int32]bool) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if !known[fieldNum] {
+			return fmt.Errorf("proto: unknown field number %d (wire type %d) rejected by UnmarshalStrict", fieldNum, wireType)
+		}
+		skippy, err := skipGeneratedWithDepthLimit(dAtA[preIndex:], defaultMaxGroupDepth)
+		if err != nil {
+			return err
+		}
+		if skippy < 0 {
+			return ErrInvalidLengthGenerated
+		}
+		iNdEx = preIndex + skippy
+	}
+	return nil
+}
+
+// UnmarshalStrict is like Unmarshal, but returns an error if dAtA contains any field number
+// that isn't part of TokenReview's known wire format, instead of silently skipping it.
+func (m *TokenReview) UnmarshalStrict(dAtA []byte) error {
+	if err := checkKnownFields(dAtA, tokenReviewFields); err != nil {
+		return err
+	}
+	return m.Unmarshal(dAtA)
+}
+
+// UnmarshalStrict is like Unmarshal, but returns an error on unknown field numbers.
+func (m *TokenReviewSpec) UnmarshalStrict(dAtA []byte) error {
+	if err := checkKnownFields(dAtA, tokenReviewSpecFields); err != nil {
+		return err
+	}
+	return m.Unmarshal(dAtA)
+}
+
+// UnmarshalStrict is like Unmarshal, but returns an error on unknown field numbers.
+func (m *TokenReviewStatus) UnmarshalStrict(dAtA []byte) error {
+	if err := checkKnownFields(dAtA, tokenReviewStatusFields); err != nil {
+		return err
+	}
+	return m.Unmarshal(dAtA)
+}
+
+// UnmarshalStrict is like Unmarshal, but returns an error on unknown field numbers.
+func (m *UserInfo) UnmarshalStrict(dAtA []byte) error {
+	if err := checkKnownFields(dAtA, userInfoFields); err != nil {
+		return err
+	}
+	return m.Unmarshal(dAtA)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+)
+
+// appendUnknownStringField appends a wire-format field with an out-of-range field number,
+// simulating a message produced by a newer schema version.
+func appendUnknownStringField(dAtA []byte, fieldNum int32, value string) []byte {
+	tag := uint64(fieldNum)<<3 | 2 // wire type 2: length-delimited
+	for tag >= 0x80 {
+		dAtA = append(dAtA, byte(tag)|0x80)
+		tag >>= 7
+	}
+	dAtA = append(dAtA, byte(tag))
+	length := uint64(len(value))
+	for length >= 0x80 {
+		dAtA = append(dAtA, byte(length)|0x80)
+		length >>= 7
+	}
+	dAtA = append(dAtA, byte(length))
+	return append(dAtA, value...)
+}
+
+func TestUserInfoUnmarshalStrict(t *testing.T) {
+	in := &UserInfo{Username: "alice", UID: "123"}
+	dAtA, err := in.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	dAtA = appendUnknownStringField(dAtA, 99, "from-the-future")
+
+	lenient := &UserInfo{}
+	if err := lenient.Unmarshal(dAtA); err != nil {
+		t.Fatalf("Unmarshal should skip unknown fields, got error: %v", err)
+	}
+	if lenient.Username != "alice" {
+		t.Errorf("expected lenient decode to preserve known fields, got %+v", lenient)
+	}
+
+	strict := &UserInfo{}
+	if err := strict.UnmarshalStrict(dAtA); err == nil {
+		t.Errorf("expected UnmarshalStrict to reject the unknown field, got nil error")
+	}
+
+	// a payload with no unknown fields should decode identically either way.
+	cleanData, err := in.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	clean := &UserInfo{}
+	if err := clean.UnmarshalStrict(cleanData); err != nil {
+		t.Errorf("UnmarshalStrict should accept a payload with only known fields, got: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// UnmarshalOptions centralizes the decode-time limits and behavior toggles that would
+// otherwise need to be duplicated across every Unmarshal variant in this package. The zero
+// value reproduces the behavior of the generated Unmarshal methods.
+type UnmarshalOptions struct {
+	// RejectUnknownFields causes Unmarshal to fail if dAtA contains a protobuf field number
+	// that is not part of the target message's known wire format, instead of silently
+	// skipping it. See UnmarshalStrict.
+	RejectUnknownFields bool
+
+	// MaxUserInfoExtraEntries, when greater than zero, caps how many entries a UserInfo's
+	// Extra map may have. It has no effect on types other than UserInfo. See
+	// UserInfo.UnmarshalWithExtraLimit.
+	MaxUserInfoExtraEntries int
+
+	// ValidateUTF8, when true, rejects a decoded message whose Token, Username, UID, or Groups
+	// fields aren't valid UTF-8. It's off by default for compatibility with existing callers
+	// that decode arbitrary bytes into these fields today. See validateUTF8Strings.
+	ValidateUTF8 bool
+
+	// MaxGroupDepth, when greater than zero, caps how deeply a payload may nest legacy proto2
+	// "groups" (wire type 3) while this package's hand-written field-skipping helpers
+	// (checkExtraEntryLimit, checkKnownFields) walk over it looking for known field numbers.
+	// Left at its zero value, those helpers fall back to defaultMaxGroupDepth rather than
+	// being unbounded, since skipGenerated's own recursion has no limit at all. See
+	// skipGeneratedWithDepthLimit.
+	MaxGroupDepth int
+
+	// LegacyUserInfoFieldNumbers, when non-empty, aliases a legacy UserInfo field number (the
+	// map key) to the field number it should be treated as today (the map value), so that a
+	// payload written by an old client before a field was renumbered can still be decoded
+	// correctly. It has no effect on types other than UserInfo. See
+	// remapTopLevelFieldNumbers.
+	LegacyUserInfoFieldNumbers map[int32]int32
+}
+
+// maxGroupDepthOrDefault returns o.MaxGroupDepth, or defaultMaxGroupDepth if it's unset.
+func (o UnmarshalOptions) maxGroupDepthOrDefault() int {
+	if o.MaxGroupDepth > 0 {
+		return o.MaxGroupDepth
+	}
+	return defaultMaxGroupDepth
+}
+
+// strictUnmarshaler is implemented by every generated message in this package via
+// UnmarshalStrict (see strict.go).
+type strictUnmarshaler interface {
+	Unmarshal(dAtA []byte) error
+	UnmarshalStrict(dAtA []byte) error
+}
+
+// Unmarshal decodes dAtA into m according to o. It is the single place new decode limits
+// should be threaded through as they're added, rather than growing the per-type
+// UnmarshalStrict signatures one option at a time.
+func (o UnmarshalOptions) Unmarshal(m strictUnmarshaler, dAtA []byte) error {
+	if o.MaxGroupDepth > 0 {
+		if err := checkGroupDepthLimit(dAtA, o.MaxGroupDepth); err != nil {
+			return err
+		}
+	}
+	if _, ok := m.(*UserInfo); ok && len(o.LegacyUserInfoFieldNumbers) > 0 {
+		remapped, err := remapTopLevelFieldNumbers(dAtA, o.LegacyUserInfoFieldNumbers)
+		if err != nil {
+			return err
+		}
+		dAtA = remapped
+	}
+	if _, ok := m.(*UserInfo); ok && o.MaxUserInfoExtraEntries > 0 {
+		if err := checkExtraEntryLimit(dAtA, o.MaxUserInfoExtraEntries, o.maxGroupDepthOrDefault()); err != nil {
+			return err
+		}
+	}
+	var err error
+	if o.RejectUnknownFields {
+		err = m.UnmarshalStrict(dAtA)
+	} else {
+		err = m.Unmarshal(dAtA)
+	}
+	if err != nil {
+		return err
+	}
+	if o.ValidateUTF8 {
+		return validateUTF8Strings(m)
+	}
+	return nil
+}
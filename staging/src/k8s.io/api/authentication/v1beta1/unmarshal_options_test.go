@@ -0,0 +1,38 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "testing"
+
+func TestUnmarshalOptions(t *testing.T) {
+	info := UserInfo{Username: "alice"}
+	dAtA, err := info.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dAtA = appendUnknownStringField(dAtA, 99, "surprise")
+
+	var lenient UserInfo
+	if err := (UnmarshalOptions{}).Unmarshal(&lenient, dAtA); err != nil {
+		t.Errorf("expected lenient Unmarshal to skip the unknown field, got: %v", err)
+	}
+
+	var strict UserInfo
+	if err := (UnmarshalOptions{RejectUnknownFields: true}).Unmarshal(&strict, dAtA); err == nil {
+		t.Errorf("expected RejectUnknownFields to reject the unknown field")
+	}
+}
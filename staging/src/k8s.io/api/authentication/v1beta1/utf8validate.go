@@ -0,0 +1,61 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// validateUTF8Strings checks that every string field the given message carries (Token,
+// Username, UID, Groups) is valid UTF-8, returning an error naming the first offending field it
+// finds. The generated Unmarshal methods accept arbitrary bytes into these fields, but
+// downstream consumers (API validation, logging, storage) assume valid UTF-8; see
+// UnmarshalOptions.ValidateUTF8.
+func validateUTF8Strings(m interface{}) error {
+	switch v := m.(type) {
+	case *TokenReview:
+		if err := validateUTF8Strings(&v.Spec); err != nil {
+			return err
+		}
+		return validateUTF8Strings(&v.Status)
+	case *TokenReviewSpec:
+		return checkUTF8("token", v.Token)
+	case *TokenReviewStatus:
+		return validateUTF8Strings(&v.User)
+	case *UserInfo:
+		if err := checkUTF8("username", v.Username); err != nil {
+			return err
+		}
+		if err := checkUTF8("uid", v.UID); err != nil {
+			return err
+		}
+		for _, group := range v.Groups {
+			if err := checkUTF8("groups", group); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkUTF8(field, value string) error {
+	if !utf8.ValidString(value) {
+		return fmt.Errorf("proto: field %q contains invalid UTF-8", field)
+	}
+	return nil
+}
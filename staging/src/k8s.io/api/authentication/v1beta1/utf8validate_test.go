@@ -0,0 +1,63 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "testing"
+
+func TestUnmarshalOptionsValidateUTF8(t *testing.T) {
+	info := UserInfo{Username: "alice\xffbob"}
+	dAtA, err := info.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var withValidation UserInfo
+	if err := (UnmarshalOptions{ValidateUTF8: true}).Unmarshal(&withValidation, dAtA); err == nil {
+		t.Errorf("expected ValidateUTF8 to reject a Username with invalid UTF-8")
+	}
+
+	var withoutValidation UserInfo
+	if err := (UnmarshalOptions{}).Unmarshal(&withoutValidation, dAtA); err != nil {
+		t.Errorf("expected the default (ValidateUTF8 off) to accept invalid UTF-8, got: %v", err)
+	}
+	if withoutValidation.Username != info.Username {
+		t.Errorf("expected the raw bytes to round trip unmodified, got %q", withoutValidation.Username)
+	}
+}
+
+func TestValidateUTF8StringsFindsInvalidFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		message interface{}
+		wantErr bool
+	}{
+		{"valid UserInfo", &UserInfo{Username: "alice", UID: "123", Groups: []string{"dev"}}, false},
+		{"invalid username", &UserInfo{Username: "ali\xffce"}, true},
+		{"invalid uid", &UserInfo{UID: "\xff"}, true},
+		{"invalid group", &UserInfo{Groups: []string{"dev", "\xff"}}, true},
+		{"valid token review spec", &TokenReviewSpec{Token: "abc"}, false},
+		{"invalid token", &TokenReviewSpec{Token: "ab\xffc"}, true},
+		{"invalid nested user in status", &TokenReviewStatus{User: UserInfo{Username: "\xff"}}, true},
+	}
+
+	for _, tc := range tests {
+		err := validateUTF8Strings(tc.message)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%v: expected error=%v, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
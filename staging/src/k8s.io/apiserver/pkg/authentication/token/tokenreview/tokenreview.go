@@ -0,0 +1,59 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tokenreview centralizes the mapping from a TokenReviewStatus back to the
+// (user.Info, bool, error) shape authenticator.Token implementations return, so webhook clients
+// don't each re-implement it.
+package tokenreview
+
+import (
+	"errors"
+
+	authenticationv1beta1 "k8s.io/api/authentication/v1beta1"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+// ToAuthenticatorResponse converts status into the (user.Info, bool, error) shape
+// authenticator.Token implementations return: the decoded user when status.Authenticated is
+// true, (nil, false, nil) when it's false, and an error when status.Error is set. status.Error
+// takes priority, matching the TokenReview webhook API contract that Error indicates the review
+// itself couldn't be completed, regardless of what Authenticated says.
+//
+// This can't be a method on TokenReviewStatus itself: that type lives in k8s.io/api, which must
+// not depend on k8s.io/apiserver's user.Info.
+func ToAuthenticatorResponse(status authenticationv1beta1.TokenReviewStatus) (user.Info, bool, error) {
+	if status.Error != "" {
+		return nil, false, errors.New(status.Error)
+	}
+	if !status.Authenticated {
+		return nil, false, nil
+	}
+
+	var extra map[string][]string
+	if status.User.Extra != nil {
+		extra = map[string][]string{}
+		for k, v := range status.User.Extra {
+			extra[k] = v
+		}
+	}
+
+	return &user.DefaultInfo{
+		Name:   status.User.Username,
+		UID:    status.User.UID,
+		Groups: status.User.Groups,
+		Extra:  extra,
+	}, true, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenreview
+
+import (
+	"reflect"
+	"testing"
+
+	authenticationv1beta1 "k8s.io/api/authentication/v1beta1"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+func TestToAuthenticatorResponseAuthenticated(t *testing.T) {
+	status := authenticationv1beta1.TokenReviewStatus{
+		Authenticated: true,
+		User: authenticationv1beta1.UserInfo{
+			Username: "alice",
+			UID:      "12345",
+			Groups:   []string{"developers"},
+			Extra: map[string]authenticationv1beta1.ExtraValue{
+				"scopes": {"read", "write"},
+			},
+		},
+	}
+
+	info, authenticated, err := ToAuthenticatorResponse(status)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !authenticated {
+		t.Fatalf("expected authenticated to be true")
+	}
+	want := &user.DefaultInfo{
+		Name:   "alice",
+		UID:    "12345",
+		Groups: []string{"developers"},
+		Extra:  map[string][]string{"scopes": {"read", "write"}},
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("expected %+v, got %+v", want, info)
+	}
+}
+
+func TestToAuthenticatorResponseNotAuthenticated(t *testing.T) {
+	status := authenticationv1beta1.TokenReviewStatus{Authenticated: false}
+
+	info, authenticated, err := ToAuthenticatorResponse(status)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if authenticated {
+		t.Fatalf("expected authenticated to be false")
+	}
+	if info != nil {
+		t.Errorf("expected a nil user.Info, got %+v", info)
+	}
+}
+
+func TestToAuthenticatorResponseError(t *testing.T) {
+	status := authenticationv1beta1.TokenReviewStatus{Error: "backend unavailable"}
+
+	info, authenticated, err := ToAuthenticatorResponse(status)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if err.Error() != "backend unavailable" {
+		t.Errorf("expected the error message to be the status Error, got %q", err.Error())
+	}
+	if authenticated {
+		t.Errorf("expected authenticated to be false")
+	}
+	if info != nil {
+		t.Errorf("expected a nil user.Info, got %+v", info)
+	}
+}
+
+func TestToAuthenticatorResponseNoExtras(t *testing.T) {
+	status := authenticationv1beta1.TokenReviewStatus{
+		Authenticated: true,
+		User:          authenticationv1beta1.UserInfo{Username: "alice"},
+	}
+
+	info, _, err := ToAuthenticatorResponse(status)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.GetExtra() != nil {
+		t.Errorf("expected a nil Extra when the status has none, got %+v", info.GetExtra())
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/cache"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/token/tokenreview"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/util/webhook"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -89,24 +90,7 @@ func (w *WebhookTokenAuthenticator) AuthenticateToken(token string) (user.Info,
 		r.Status = result.Status
 		w.responseCache.Add(r.Spec, result.Status, w.ttl)
 	}
-	if !r.Status.Authenticated {
-		return nil, false, nil
-	}
-
-	var extra map[string][]string
-	if r.Status.User.Extra != nil {
-		extra = map[string][]string{}
-		for k, v := range r.Status.User.Extra {
-			extra[k] = v
-		}
-	}
-
-	return &user.DefaultInfo{
-		Name:   r.Status.User.Username,
-		UID:    r.Status.User.UID,
-		Groups: r.Status.User.Groups,
-		Extra:  extra,
-	}, true, nil
+	return tokenreview.ToAuthenticatorResponse(r.Status)
 }
 
 // NOTE: client-go doesn't provide a registry. client-go does registers the
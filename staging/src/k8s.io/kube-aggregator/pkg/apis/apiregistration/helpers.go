@@ -92,7 +92,7 @@ func NewLocalAvailableAPIServiceCondition() APIServiceCondition {
 		Type:               Available,
 		Status:             ConditionTrue,
 		LastTransitionTime: metav1.Now(),
-		Reason:             "Local",
+		Reason:             string(AvailabilityReasonLocal),
 		Message:            "Local APIServices are always available",
 	}
 }
@@ -121,6 +121,39 @@ func SetAPIServiceCondition(apiService *APIService, newCondition APIServiceCondi
 	existingCondition.Message = newCondition.Message
 }
 
+// GetAvailabilityReason returns the Available condition's Reason as an AvailabilityReason,
+// along with whether that reason is one this version of the code recognizes. Callers that
+// need to branch on the well-known outcomes should prefer this over comparing Reason strings
+// directly, since it makes unrecognized (e.g. newer) reasons explicit.
+func GetAvailabilityReason(apiService *APIService) (reason AvailabilityReason, known bool) {
+	for _, condition := range apiService.Status.Conditions {
+		if condition.Type != Available {
+			continue
+		}
+		reason = AvailabilityReason(condition.Reason)
+		switch reason {
+		case AvailabilityReasonLocal, AvailabilityReasonPassed, AvailabilityReasonServiceNotFound,
+			AvailabilityReasonServiceAccessError, AvailabilityReasonEndpointsNotFound,
+			AvailabilityReasonEndpointsAccessError, AvailabilityReasonMissingEndpoints,
+			AvailabilityReasonFailedDiscoveryCheck, AvailabilityReasonNamespaceTerminating,
+			AvailabilityReasonCheckSkipped, AvailabilityReasonServiceDNSResolutionFailed,
+			AvailabilityReasonLocalHealthCheckFailed, AvailabilityReasonDiscoveryProtocolError,
+			AvailabilityReasonEndpointsNotAllReady, AvailabilityReasonEndpointsNotMajorityReady,
+			AvailabilityReasonServiceHasNoSelector, AvailabilityReasonServiceConnectionRefused,
+			AvailabilityReasonDiscoveryTimeout, AvailabilityReasonDiscoveryBadContentType,
+			AvailabilityReasonInvalidCABundle, AvailabilityReasonServiceNotFoundPending,
+			AvailabilityReasonMissingGroupVersion, AvailabilityReasonNoLocalZoneEndpoints,
+			AvailabilityReasonEndpointsTerminating, AvailabilityReasonAwaitingConsecutiveSuccesses,
+			AvailabilityReasonDiscoveryServerError, AvailabilityReasonDiscoveryNotFound,
+			AvailabilityReasonGroupVersionNotAdvertised, AvailabilityReasonNoReadyPorts,
+			AvailabilityReasonCircuitBreakerOpen, AvailabilityReasonDiscoveryUnexpectedRedirect:
+			return reason, true
+		}
+		return reason, false
+	}
+	return "", false
+}
+
 // IsAPIServiceConditionTrue indicates if the condition is present and strictly true
 func IsAPIServiceConditionTrue(apiService *APIService, conditionType APIServiceConditionType) bool {
 	for _, condition := range apiService.Status.Conditions {
@@ -130,3 +163,25 @@ func IsAPIServiceConditionTrue(apiService *APIService, conditionType APIServiceC
 	}
 	return false
 }
+
+// Equal reports whether c and other represent the same condition, ignoring LastTransitionTime.
+// Callers comparing conditions to decide whether something actually changed -- rather than just
+// being re-evaluated with the same outcome -- want this instead of a plain ==, since
+// LastTransitionTime is expected to be fresh on every evaluation.
+func (c APIServiceCondition) Equal(other APIServiceCondition) bool {
+	return c.Type == other.Type &&
+		c.Status == other.Status &&
+		c.Reason == other.Reason &&
+		c.Message == other.Message
+}
+
+// GetAPIServiceConditionByType returns a pointer to apiService's condition of the given type,
+// or nil if it has none.
+func GetAPIServiceConditionByType(apiService *APIService, conditionType APIServiceConditionType) *APIServiceCondition {
+	for i := range apiService.Status.Conditions {
+		if apiService.Status.Conditions[i].Type == conditionType {
+			return &apiService.Status.Conditions[i]
+		}
+	}
+	return nil
+}
@@ -44,6 +44,15 @@ type APIServiceSpec struct {
 	// If the Service is nil, that means the handling for the API groupversion is handled locally on this server.
 	// The call will simply delegate to the normal handler chain to be fulfilled.
 	Service *ServiceReference
+	// FallbackServices lists additional service references to probe for availability when Service
+	// itself doesn't resolve or fails its discovery probe. Service remains the primary backend;
+	// the AvailableConditionController reports Available as ConditionTrue if Service or any
+	// FallbackService passes; when a FallbackService is the one that passed, the condition's
+	// message names the failed primary and the fallback in use. This
+	// supports migrating an aggregated API from one backing Service to another without a window
+	// where it's marked unavailable. Internal-only for now -- not yet plumbed through to a
+	// versioned API, since that also requires generated protobuf marshal/unmarshal support.
+	FallbackServices []ServiceReference
 	// Group is the API group name this server hosts
 	Group string
 	// Version is the API version this server hosts.  For example, "v1"
@@ -88,8 +97,182 @@ type APIServiceConditionType string
 const (
 	// Available indicates that the service exists and is reachable
 	Available APIServiceConditionType = "Available"
+	// Degraded indicates that the service is reachable but not fully healthy -- for example
+	// because some, but not all, of its backing endpoints are ready. An APIService can be
+	// Available and Degraded at the same time: Degraded only refines what an Available=True
+	// service's health looks like, it doesn't override it.
+	Degraded APIServiceConditionType = "Degraded"
 )
 
+// AvailabilityReason is a known, machine-readable value for APIServiceCondition.Reason on the
+// Available condition type. Reason itself stays a free-text string for forward-compatibility
+// with reasons this version of the code doesn't know about, but callers that only care about
+// the well-known outcomes can compare against these constants instead of string literals.
+type AvailabilityReason string
+
+const (
+	// AvailabilityReasonLocal is used when the APIService has no backing Service and is
+	// therefore always considered available.
+	AvailabilityReasonLocal AvailabilityReason = "Local"
+	// AvailabilityReasonPassed is used when the discovery probe and all prerequisite checks succeeded.
+	AvailabilityReasonPassed AvailabilityReason = "Passed"
+	// AvailabilityReasonServiceNotFound is used when the backing Service does not exist.
+	AvailabilityReasonServiceNotFound AvailabilityReason = "ServiceNotFound"
+	// AvailabilityReasonServiceAccessError is used when the backing Service could not be retrieved.
+	AvailabilityReasonServiceAccessError AvailabilityReason = "ServiceAccessError"
+	// AvailabilityReasonEndpointsNotFound is used when the backing Service has no Endpoints object.
+	AvailabilityReasonEndpointsNotFound AvailabilityReason = "EndpointsNotFound"
+	// AvailabilityReasonEndpointsAccessError is used when the Endpoints object could not be retrieved.
+	AvailabilityReasonEndpointsAccessError AvailabilityReason = "EndpointsAccessError"
+	// AvailabilityReasonMissingEndpoints is used when the Endpoints object has no ready addresses.
+	AvailabilityReasonMissingEndpoints AvailabilityReason = "MissingEndpoints"
+	// AvailabilityReasonFailedDiscoveryCheck is used when the discovery probe failed.
+	AvailabilityReasonFailedDiscoveryCheck AvailabilityReason = "FailedDiscoveryCheck"
+	// AvailabilityReasonNamespaceTerminating is used when the backing Service's namespace is
+	// being deleted, which explains why the Service is also missing or about to disappear.
+	AvailabilityReasonNamespaceTerminating AvailabilityReason = "NamespaceTerminating"
+	// AvailabilityReasonCheckSkipped is used when availability checks were bypassed entirely
+	// because of DisableAvailabilityCheckAnnotation.
+	AvailabilityReasonCheckSkipped AvailabilityReason = "AvailabilityCheckSkipped"
+	// AvailabilityReasonServiceDNSResolutionFailed is used when the discovery probe failed
+	// because the backing Service's DNS name could not be resolved.
+	AvailabilityReasonServiceDNSResolutionFailed AvailabilityReason = "ServiceDNSResolutionFailed"
+	// AvailabilityReasonLocalHealthCheckFailed is used when a local APIService's optional
+	// readiness hook reports that the local handler isn't ready yet.
+	AvailabilityReasonLocalHealthCheckFailed AvailabilityReason = "LocalHealthCheckFailed"
+	// AvailabilityReasonDiscoveryProtocolError is used when the discovery probe failed because
+	// of an HTTP/2-specific error (a GOAWAY, stream, or connection error) rather than a plain
+	// timeout or refused connection.
+	AvailabilityReasonDiscoveryProtocolError AvailabilityReason = "DiscoveryProtocolError"
+	// AvailabilityReasonEndpointsNotAllReady is used under EndpointReadinessPolicyRequireAll
+	// when at least one endpoint address is not ready.
+	AvailabilityReasonEndpointsNotAllReady AvailabilityReason = "EndpointsNotAllReady"
+	// AvailabilityReasonEndpointsNotMajorityReady is used under
+	// EndpointReadinessPolicyRequireMajority when ready addresses are not a strict majority of
+	// all known addresses.
+	AvailabilityReasonEndpointsNotMajorityReady AvailabilityReason = "EndpointsNotMajorityReady"
+	// AvailabilityReasonServiceHasNoSelector is used instead of AvailabilityReasonEndpointsNotFound
+	// when the backing Service has no Endpoints and also has an empty selector, meaning the
+	// endpoints controller will never populate them -- some other, manually-managed mechanism
+	// is expected to create the Endpoints object instead.
+	AvailabilityReasonServiceHasNoSelector AvailabilityReason = "ServiceHasNoSelector"
+	// AvailabilityReasonServiceConnectionRefused is used when the discovery probe failed because
+	// nothing was listening on the backing Service's resolved address (ECONNREFUSED), which
+	// points at the backend process rather than the network.
+	AvailabilityReasonServiceConnectionRefused AvailabilityReason = "ServiceConnectionRefused"
+	// AvailabilityReasonDiscoveryTimeout is used when the discovery probe failed because no
+	// response arrived before its deadline, which points at a slow or hung backend rather than
+	// one that's outright unreachable.
+	AvailabilityReasonDiscoveryTimeout AvailabilityReason = "DiscoveryTimeout"
+	// AvailabilityReasonDiscoveryBadContentType is used when the discovery probe got a 2xx
+	// response with a Content-Type other than the one required via
+	// AvailableConditionController.SetRequiredDiscoveryContentType -- for example an HTML login
+	// redirect page served with a 200 instead of the expected discovery document.
+	AvailabilityReasonDiscoveryBadContentType AvailabilityReason = "DiscoveryBadContentType"
+	// AvailabilityReasonInvalidCABundle is used when CABundle is set but cannot be parsed into a
+	// certificate pool, which would otherwise surface as an opaque TLS configuration error at
+	// probe time.
+	AvailabilityReasonInvalidCABundle AvailabilityReason = "InvalidCABundle"
+	// AvailabilityReasonServiceNotFoundPending is used instead of AvailabilityReasonServiceNotFound
+	// when the backing Service is missing but the APIService is still within its configured
+	// AvailableConditionController.ServiceNotFoundGracePeriod, so a Service that simply hasn't
+	// been created yet during bootstrap doesn't read the same as one that's unexpectedly gone.
+	AvailabilityReasonServiceNotFoundPending AvailabilityReason = "ServiceNotFoundPending"
+	// AvailabilityReasonMissingGroupVersion is used when the APIService has an empty group or
+	// version, which can never be meaningfully probed, instead of proceeding to a discovery
+	// failure that wouldn't explain the real problem.
+	AvailabilityReasonMissingGroupVersion AvailabilityReason = "MissingGroupVersion"
+	// AvailabilityReasonNoLocalZoneEndpoints is used when
+	// AvailableConditionController.LocalZone is configured and a backing Service has ready
+	// endpoints, but none of them resolve to the aggregator's own zone, so routing to the
+	// backend would cross a zone boundary.
+	AvailabilityReasonNoLocalZoneEndpoints AvailabilityReason = "NoLocalZoneEndpoints"
+	// AvailabilityReasonEndpointsTerminating is used instead of the more generic
+	// AvailabilityReasonMissingEndpoints when every known address is not-ready and
+	// AvailableConditionController.PodTerminatingLookup identifies all of them as backed by a
+	// terminating pod, which distinguishes a service draining during a rollout from one that's
+	// actually broken.
+	AvailabilityReasonEndpointsTerminating AvailabilityReason = "EndpointsTerminating"
+	// AvailabilityReasonAwaitingConsecutiveSuccesses is used when every check otherwise passed,
+	// but AvailableConditionController.MinConsecutiveSuccesses requires more consecutive
+	// successful syncs than this APIService has accumulated so far, to avoid flapping to
+	// available on a single lucky probe during a rollout.
+	AvailabilityReasonAwaitingConsecutiveSuccesses AvailabilityReason = "AwaitingConsecutiveSuccesses"
+	// AvailabilityReasonDiscoveryServerError is used instead of the more generic
+	// AvailabilityReasonFailedDiscoveryCheck when the discovery probe got a 5xx response, which
+	// points at an error in the backend itself rather than a routing or configuration problem.
+	AvailabilityReasonDiscoveryServerError AvailabilityReason = "DiscoveryServerError"
+	// AvailabilityReasonDiscoveryNotFound is used instead of the more generic
+	// AvailabilityReasonFailedDiscoveryCheck when the discovery probe got a 404, which points at
+	// the discovery path itself being wrong rather than the backend being unhealthy.
+	AvailabilityReasonDiscoveryNotFound AvailabilityReason = "DiscoveryNotFound"
+
+	// AvailabilityReasonGroupVersionNotAdvertised is used when the discovery probe otherwise
+	// succeeded, but AvailableConditionController.ValidateAdvertisedGroupVersion is enabled and
+	// the discovery document returned doesn't advertise this APIService's group/version, which
+	// usually means the backing Service is routing to the wrong backend.
+	AvailabilityReasonGroupVersionNotAdvertised AvailabilityReason = "GroupVersionNotAdvertised"
+
+	// AvailabilityReasonNoReadyPorts is used when a Service has ready endpoint addresses, but none
+	// of them are served on any of the Service's own ports, which can happen briefly during
+	// startup while the endpoints controller is still catching up to a Service port change.
+	AvailabilityReasonNoReadyPorts AvailabilityReason = "NoReadyPorts"
+	// AvailabilityReasonCircuitBreakerOpen is used when AvailableConditionController.
+	// CircuitBreakerThreshold is enabled and the backing host's circuit breaker is open because of
+	// repeated consecutive probe failures, so the probe was skipped rather than repeated against a
+	// backend already known to be down.
+	AvailabilityReasonCircuitBreakerOpen AvailabilityReason = "CircuitBreakerOpen"
+	// AvailabilityReasonDiscoveryUnexpectedRedirect is used when the discovery probe got a 3xx
+	// redirect response, for example a backend sending unauthenticated requests to a login page.
+	// The probe doesn't follow redirects, so this is reported rather than whatever the redirect
+	// target would have returned.
+	AvailabilityReasonDiscoveryUnexpectedRedirect AvailabilityReason = "DiscoveryUnexpectedRedirect"
+)
+
+// DegradedReason is a known, machine-readable value for APIServiceCondition.Reason on the
+// Degraded condition type, playing the same role AvailabilityReason plays for Available.
+type DegradedReason string
+
+const (
+	// DegradedReasonEndpointsPartiallyReady is used when at least the configured minimum number
+	// of a backing Service's endpoint addresses are ready (so Available is True), but not every
+	// known address is.
+	DegradedReasonEndpointsPartiallyReady DegradedReason = "EndpointsPartiallyReady"
+	// DegradedReasonEndpointsFullyReady is used when every known endpoint address is ready.
+	DegradedReasonEndpointsFullyReady DegradedReason = "EndpointsFullyReady"
+)
+
+// DisableAvailabilityCheckAnnotation, when set to "true" on an APIService, makes the
+// availability controller report it as available unconditionally instead of checking its
+// backing Service and discovery endpoint. It is an escape hatch for APIServices whose
+// availability genuinely can't be checked this way (for example, because nothing is listening
+// on the Service yet during bring-up), and should not be used to paper over real failures.
+const DisableAvailabilityCheckAnnotation = "apiregistration.k8s.io/disable-availability-check"
+
+// ProbeHostOverrideAnnotation, when set on an APIService, is sent as the discovery probe
+// request's Host header (and TLS server name) instead of the backing Service's in-cluster DNS
+// name. This is for split-horizon DNS setups where the aggregator can only reach the backend
+// through an address that doesn't itself answer to the name the backend's certificate and
+// virtual-hosting expect -- the probe still dials the resolved Service address, only the
+// request's notion of which host it's talking to changes.
+const ProbeHostOverrideAnnotation = "apiregistration.k8s.io/probe-host"
+
+// AvailabilityHistoryAnnotation holds a JSON-encoded, size-capped ring buffer of the Available
+// condition's most recent Reason transitions and their timestamps, maintained by the
+// availability controller for flap detection -- a single Available condition only ever shows
+// the current Reason, which makes it impossible to tell from the APIService alone whether it's
+// been stable or has been flipping reasons every few syncs. This is maintained internally by the
+// controller; writing to it directly has no effect.
+const AvailabilityHistoryAnnotation = "apiregistration.k8s.io/availability-history"
+
+// CrossClusterServiceAnnotation, when set to "true" on an APIService, tells the availability
+// controller that the backing Service's endpoints intentionally live in a different cluster and
+// will never show up in the local Endpoints lister, so it should skip the local endpoint-readiness
+// checks entirely and rely solely on the discovery probe succeeding. Without it, a multi-cluster
+// APIService would be permanently reported AvailabilityReasonEndpointsNotFound, since nothing
+// local ever populates Endpoints for its Service.
+const CrossClusterServiceAnnotation = "apiregistration.k8s.io/cross-cluster-service"
+
 // APIServiceCondition describes conditions for an APIService
 type APIServiceCondition struct {
 	// Type is the type of the condition.
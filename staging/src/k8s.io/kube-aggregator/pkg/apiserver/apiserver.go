@@ -210,6 +210,7 @@ func (c completedConfig) NewWithDelegate(delegationTarget genericapiserver.Deleg
 		c.ExtraConfig.ProxyTransport,
 		s.serviceResolver,
 	)
+	availableController.SetNamespaceLister(c.GenericConfig.SharedInformerFactory.Core().V1().Namespaces().Lister())
 
 	s.GenericAPIServer.AddPostStartHook("start-kube-aggregator-informers", func(context genericapiserver.PostStartHookContext) error {
 		informerFactory.Start(context.StopCh)
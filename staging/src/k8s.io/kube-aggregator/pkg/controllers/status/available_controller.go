@@ -17,13 +17,28 @@ limitations under the License.
 package apiserver
 
 import (
+	"compress/gzip"
+	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/golang/glog"
+	"golang.org/x/net/http2"
 
 	"k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -31,11 +46,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/clock"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	v1informers "k8s.io/client-go/informers/core/v1"
 	v1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/client-go/util/workqueue"
 
 	"k8s.io/kube-aggregator/pkg/apis/apiregistration"
@@ -49,6 +67,476 @@ type ServiceResolver interface {
 	ResolveEndpoint(namespace, name string) (*url.URL, error)
 }
 
+// Prober checks whether a discovery endpoint is reachable and healthy. It exists so that
+// tests can substitute a fake implementation instead of going through a real HTTP stack.
+// tlsConfig controls how the probe validates the endpoint's serving certificate; see
+// tlsConfigForAPIService. probeHost, when non-empty, overrides the request's Host header and TLS
+// server name -- see apiregistration.ProbeHostOverrideAnnotation -- and is otherwise left empty
+// to use discoveryURL's own host. expectedGroupVersion, when non-empty, additionally requires the
+// discovery document to advertise that exact "group/version" -- see
+// AvailableConditionController.ValidateAdvertisedGroupVersion -- and is otherwise left empty to
+// skip that check, which is this interface's historical behavior.
+type Prober interface {
+	Probe(discoveryURL *url.URL, tlsConfig *tls.Config, probeHost, expectedGroupVersion string) error
+}
+
+// ContextProber is an optional extension of Prober for implementations that can abort an
+// in-flight probe when ctx is canceled, used by syncWithContext when the configured Prober
+// supports it. clientProber, the default implementation, satisfies this.
+type ContextProber interface {
+	ProbeContext(ctx context.Context, discoveryURL *url.URL, tlsConfig *tls.Config, probeHost, expectedGroupVersion string) error
+}
+
+// probe calls c.prober, routing through ProbeContext when the configured Prober implements
+// ContextProber so ctx can abort an in-flight probe, and falling back to the plain Probe method
+// otherwise. When MaxConcurrentProbesPerHost is configured, it first waits for a free slot in
+// discoveryURL.Host's semaphore, so APIServices that share a backing host don't all probe it at
+// once.
+func (c *AvailableConditionController) probe(ctx context.Context, discoveryURL *url.URL, tlsConfig *tls.Config, probeHost, expectedGroupVersion string) error {
+	release, err := c.acquireProbeSlot(ctx, discoveryURL.Host)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	var breaker *circuitBreaker
+	if c.CircuitBreakerThreshold > 0 {
+		breaker = c.circuitBreakerForHost(discoveryURL.Host)
+		if ok, lastErr := breaker.allow(c.clockOrDefault().Now(), c.CircuitBreakerCooldown); !ok {
+			return &ErrCircuitBreakerOpen{Host: discoveryURL.Host, LastErr: lastErr}
+		}
+	}
+
+	var probeErr error
+	if cp, ok := c.prober.(ContextProber); ok {
+		probeErr = cp.ProbeContext(ctx, discoveryURL, tlsConfig, probeHost, expectedGroupVersion)
+	} else {
+		probeErr = c.prober.Probe(discoveryURL, tlsConfig, probeHost, expectedGroupVersion)
+	}
+
+	if breaker != nil {
+		if probeErr != nil {
+			breaker.recordFailure(c.clockOrDefault().Now(), c.CircuitBreakerThreshold, probeErr)
+		} else {
+			breaker.recordSuccess()
+		}
+	}
+	return probeErr
+}
+
+// acquireProbeSlot blocks until a concurrent-probe slot for host becomes available, returning a
+// func that releases it once the probe completes. It's a no-op, always-available slot when
+// MaxConcurrentProbesPerHost is unset (the default). Returns ctx.Err() if ctx is canceled first.
+func (c *AvailableConditionController) acquireProbeSlot(ctx context.Context, host string) (func(), error) {
+	if c.MaxConcurrentProbesPerHost <= 0 {
+		return func() {}, nil
+	}
+	sem := c.probeSemaphoreForHost(host)
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// probeSemaphoreForHost returns the counting semaphore used to bound concurrent probes against
+// host, lazily creating one sized to MaxConcurrentProbesPerHost the first time host is seen.
+func (c *AvailableConditionController) probeSemaphoreForHost(host string) chan struct{} {
+	c.probeSemaphoresMu.Lock()
+	defer c.probeSemaphoresMu.Unlock()
+	if c.probeSemaphores == nil {
+		c.probeSemaphores = make(map[string]chan struct{})
+	}
+	sem, ok := c.probeSemaphores[host]
+	if !ok {
+		sem = make(chan struct{}, c.MaxConcurrentProbesPerHost)
+		c.probeSemaphores[host] = sem
+	}
+	return sem
+}
+
+// ErrProbeUnauthorized is returned by clientProber.Probe when the discovery endpoint responded
+// with 401 or 403. Whether that counts as "available" is a controller-level policy decision
+// (see AvailableConditionController.TreatUnauthorizedAsAvailable), not something the prober
+// itself should decide.
+type ErrProbeUnauthorized struct {
+	StatusCode int
+}
+
+func (e *ErrProbeUnauthorized) Error() string {
+	return fmt.Sprintf("discovery endpoint returned %d", e.StatusCode)
+}
+
+// ErrProbeFailed is returned by clientProber.Probe when the discovery endpoint responded with a
+// non-2xx status other than 401/403 (see ErrProbeUnauthorized). Headers holds whichever of the
+// response's headers were in the prober's ResponseHeaderAllowlist, for diagnostics.
+type ErrProbeFailed struct {
+	StatusCode int
+	Headers    http.Header
+}
+
+func (e *ErrProbeFailed) Error() string {
+	msg := fmt.Sprintf("discovery endpoint returned %d", e.StatusCode)
+	if headers := formatProbeHeaders(e.Headers); headers != "" {
+		msg += fmt.Sprintf(" (%s)", headers)
+	}
+	return msg
+}
+
+// ErrProbeTimeout is returned by clientProber.Probe when no response arrives before the probe's
+// own deadline. It's a distinct type (rather than a bare fmt.Errorf) so sync can recognize it and
+// report the more specific DiscoveryTimeout reason instead of the generic FailedDiscoveryCheck.
+type ErrProbeTimeout struct {
+	URL *url.URL
+}
+
+func (e *ErrProbeTimeout) Error() string {
+	return fmt.Sprintf("timed out waiting for %v", e.URL)
+}
+
+// ErrProbeBadContentType is returned by clientProber.Probe when the discovery endpoint responds
+// with a 2xx status but a Content-Type other than clientProber.RequiredContentType -- for example
+// a login redirect page served as text/html instead of the expected discovery document.
+type ErrProbeBadContentType struct {
+	ContentType string
+	Required    string
+}
+
+func (e *ErrProbeBadContentType) Error() string {
+	return fmt.Sprintf("discovery endpoint returned content-type %q, want %q", e.ContentType, e.Required)
+}
+
+// ErrProbeRedirect is returned by clientProber.Probe when the discovery endpoint responds with a
+// 3xx redirect, for example to an unrelated login page. The probe client doesn't follow redirects
+// (see noFollowRedirects), so this reports the redirect itself rather than whatever the Location
+// header points to.
+type ErrProbeRedirect struct {
+	StatusCode int
+	Location   string
+}
+
+func (e *ErrProbeRedirect) Error() string {
+	return fmt.Sprintf("discovery endpoint returned %d redirect to %q", e.StatusCode, e.Location)
+}
+
+// ErrGroupVersionNotAdvertised is returned by clientProber.Probe when the probe otherwise
+// succeeded, but expectedGroupVersion was non-empty and the discovery document returned didn't
+// advertise it -- usually a sign that the backing Service is routing to the wrong backend.
+type ErrGroupVersionNotAdvertised struct {
+	Expected string
+	Actual   string
+}
+
+func (e *ErrGroupVersionNotAdvertised) Error() string {
+	if e.Actual == "" {
+		return fmt.Sprintf("discovery response didn't advertise group/version %q", e.Expected)
+	}
+	return fmt.Sprintf("discovery response advertised group/version %q, want %q", e.Actual, e.Expected)
+}
+
+// contentTypeMatches reports whether contentType (an HTTP Content-Type header value, possibly
+// with parameters like "; charset=utf-8") identifies the same media type as required, ignoring
+// parameters and case.
+func contentTypeMatches(contentType, required string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(mediaType, required)
+}
+
+// maxProbeFailureHeaderValueLength bounds how much of a single captured header value is included
+// in a failure message, so a misbehaving backend can't blow up the APIService's status message
+// with an oversized header.
+const maxProbeFailureHeaderValueLength = 256
+
+// formatProbeHeaders renders headers as "Key: Value, Key: Value" in a deterministic order,
+// truncating any value longer than maxProbeFailureHeaderValueLength.
+func formatProbeHeaders(headers http.Header) string {
+	var parts []string
+	for key, values := range headers {
+		for _, value := range values {
+			if len(value) > maxProbeFailureHeaderValueLength {
+				value = value[:maxProbeFailureHeaderValueLength] + "..."
+			}
+			parts = append(parts, fmt.Sprintf("%s: %s", key, value))
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// captureHeaders returns the subset of headers whose keys are in allowlist.
+func captureHeaders(headers http.Header, allowlist []string) http.Header {
+	captured := http.Header{}
+	for _, key := range allowlist {
+		if values := headers[http.CanonicalHeaderKey(key)]; len(values) > 0 {
+			captured[http.CanonicalHeaderKey(key)] = values
+		}
+	}
+	return captured
+}
+
+// defaultProbeResponseHeaderAllowlist lists the response headers captured into a failed probe's
+// diagnostic message when clientProber.ResponseHeaderAllowlist is unset: Retry-After for backoff
+// hints, and X-Request-Id for correlating with backend logs.
+var defaultProbeResponseHeaderAllowlist = []string{"Retry-After", "X-Request-Id"}
+
+// defaultProbeUserAgent is sent on discovery probes when no UserAgent is configured.
+const defaultProbeUserAgent = "kube-aggregator-available-condition-controller"
+
+// clientProber is the default Prober, backed by an *http.Client.
+type clientProber struct {
+	discoveryClient *http.Client
+	// UserAgent is sent as the User-Agent header on every probe request. Defaults to
+	// defaultProbeUserAgent when empty, which keeps probes identifiable in server access logs.
+	UserAgent string
+	// ResponseHeaderAllowlist names the response headers captured into an ErrProbeFailed's
+	// message on a failing probe. Defaults to defaultProbeResponseHeaderAllowlist when empty.
+	ResponseHeaderAllowlist []string
+	// RequiredContentType, when non-empty, makes an otherwise-successful probe fail with
+	// ErrProbeBadContentType unless the response's Content-Type (ignoring parameters) matches it.
+	// Left empty by default, since not every backend's discovery document is JSON.
+	RequiredContentType string
+	// AcceptableStatusCodes, when non-empty, replaces the default 200-299 range as the set of
+	// response status codes that count as a successful probe. Some discovery backends legitimately
+	// respond 204 or 304 instead of 200, and shouldn't be treated as failing because of it.
+	AcceptableStatusCodes []int
+	// Proxy determines the proxy used for discovery probe requests, with the same semantics as
+	// http.Transport.Proxy. Defaults to http.ProxyFromEnvironment when nil, so an operator can
+	// route probe traffic through an egress proxy via the usual HTTP_PROXY/HTTPS_PROXY/NO_PROXY
+	// environment variables without any explicit configuration. This is deliberately independent
+	// of the proxy transport the aggregator uses to forward actual API requests to backends.
+	Proxy func(*http.Request) (*url.URL, error)
+	// Headers, when non-empty, are set verbatim on every discovery probe request, for backends
+	// that require an identity or routing header (e.g. a tenant id) to serve discovery. Left
+	// empty by default, since no backend needs this historically.
+	Headers http.Header
+	// MaxIdleConnsPerHost caps how many idle connections the probe transport keeps open per
+	// backend host, passed straight through to http.Transport.MaxIdleConnsPerHost. Defaults to
+	// 0, which makes http.Transport fall back to its own default
+	// (http.DefaultMaxIdleConnsPerHost, 2). Lowering this in a large cluster with many backends
+	// keeps probe connections from piling up and exhausting file descriptors.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout bounds how long the probe transport keeps an idle connection open before
+	// closing it, passed straight through to http.Transport.IdleConnTimeout. Defaults to 0,
+	// which makes http.Transport keep idle connections open indefinitely.
+	IdleConnTimeout time.Duration
+	// ClientCertificates, when non-empty, are presented to the backend during the probe's TLS
+	// handshake, for backends configured to require mutual TLS and reject anonymous probes with
+	// AvailabilityReasonFailedDiscoveryCheck. Left empty by default, since most backends don't
+	// require a client certificate for discovery.
+	ClientCertificates []tls.Certificate
+}
+
+// noFollowRedirects is used as the discovery probe http.Client's CheckRedirect, so a 3xx response
+// (for example a login page an unauthenticated probe gets redirected to) is reported to the probe
+// as ErrProbeRedirect instead of silently being followed into an unrelated endpoint.
+func noFollowRedirects(req *http.Request, via []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// proxyOrDefault returns p.Proxy, or http.ProxyFromEnvironment when unset.
+func (p *clientProber) proxyOrDefault() func(*http.Request) (*url.URL, error) {
+	if p.Proxy != nil {
+		return p.Proxy
+	}
+	return http.ProxyFromEnvironment
+}
+
+// buildTransport constructs the per-request *http.Transport used by ProbeContext when a TLS
+// config is in play, applying p.MaxIdleConnsPerHost and p.IdleConnTimeout and enabling HTTP/2.
+func (p *clientProber) buildTransport(tlsConfig *tls.Config) *http.Transport {
+	if len(p.ClientCertificates) > 0 {
+		tlsConfig.Certificates = p.ClientCertificates
+	}
+	transport := &http.Transport{
+		TLSClientConfig:     tlsConfig,
+		Proxy:               p.proxyOrDefault(),
+		MaxIdleConnsPerHost: p.MaxIdleConnsPerHost,
+		IdleConnTimeout:     p.IdleConnTimeout,
+	}
+	if err := http2.ConfigureTransport(transport); err != nil {
+		glog.V(4).Infof("Failed to enable HTTP/2 for discovery probe: %v", err)
+	}
+	return transport
+}
+
+// responseHeaderAllowlist returns p.ResponseHeaderAllowlist, or defaultProbeResponseHeaderAllowlist
+// when unset.
+func (p *clientProber) responseHeaderAllowlist() []string {
+	if len(p.ResponseHeaderAllowlist) > 0 {
+		return p.ResponseHeaderAllowlist
+	}
+	return defaultProbeResponseHeaderAllowlist
+}
+
+// isAcceptableStatus reports whether statusCode counts as a successful probe response: either a
+// member of p.AcceptableStatusCodes when configured, or the default 2xx range otherwise.
+func (p *clientProber) isAcceptableStatus(statusCode int) bool {
+	if len(p.AcceptableStatusCodes) == 0 {
+		return statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices
+	}
+	for _, acceptable := range p.AcceptableStatusCodes {
+		if statusCode == acceptable {
+			return true
+		}
+	}
+	return false
+}
+
+// Probe is ProbeContext using context.Background(), for callers that don't need to cancel or
+// trace an in-flight probe.
+func (p *clientProber) Probe(discoveryURL *url.URL, tlsConfig *tls.Config, probeHost, expectedGroupVersion string) error {
+	return p.ProbeContext(context.Background(), discoveryURL, tlsConfig, probeHost, expectedGroupVersion)
+}
+
+// ProbeContext is Probe, but aborts the in-flight request as soon as ctx is canceled instead of
+// only giving up after the 6-second insurance timeout below.
+func (p *clientProber) ProbeContext(ctx context.Context, discoveryURL *url.URL, tlsConfig *tls.Config, probeHost, expectedGroupVersion string) error {
+	if probeHost != "" {
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		} else {
+			tlsConfig = tlsConfig.Clone()
+		}
+		tlsConfig.ServerName = probeHost
+	}
+
+	client := p.discoveryClient
+	if tlsConfig != nil {
+		client = &http.Client{
+			Transport:     p.buildTransport(tlsConfig),
+			Timeout:       p.discoveryClient.Timeout,
+			CheckRedirect: noFollowRedirects,
+		}
+	}
+
+	errCh := make(chan error)
+	go func() {
+		req, err := http.NewRequest("GET", discoveryURL.String(), nil)
+		if err != nil {
+			errCh <- err
+			return
+		}
+		req = req.WithContext(ctx)
+		if probeHost != "" {
+			req.Host = probeHost
+		}
+		userAgent := p.UserAgent
+		if userAgent == "" {
+			userAgent = defaultProbeUserAgent
+		}
+		req.Header.Set("User-Agent", userAgent)
+		// Ask explicitly for gzip so backends that only compress in response to an explicit
+		// Accept-Encoding don't fail content negotiation; drainProbeResponseBody below decodes
+		// it before discarding.
+		req.Header.Set("Accept-Encoding", "gzip")
+		for key, values := range p.Headers {
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+
+		resp, err := client.Do(req)
+		if resp != nil {
+			if err == nil {
+				switch {
+				case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+					err = &ErrProbeUnauthorized{StatusCode: resp.StatusCode}
+				case !p.isAcceptableStatus(resp.StatusCode) && resp.StatusCode >= http.StatusMultipleChoices && resp.StatusCode < http.StatusBadRequest:
+					// a 3xx is only treated as an unexpected redirect if it isn't one of the
+					// backend's explicitly whitelisted AcceptableStatusCodes -- some backends
+					// legitimately respond 304, which isAcceptableStatus already knows about.
+					err = &ErrProbeRedirect{StatusCode: resp.StatusCode, Location: resp.Header.Get("Location")}
+				case !p.isAcceptableStatus(resp.StatusCode):
+					err = &ErrProbeFailed{
+						StatusCode: resp.StatusCode,
+						Headers:    captureHeaders(resp.Header, p.responseHeaderAllowlist()),
+					}
+				case p.RequiredContentType != "" && !contentTypeMatches(resp.Header.Get("Content-Type"), p.RequiredContentType):
+					err = &ErrProbeBadContentType{
+						ContentType: resp.Header.Get("Content-Type"),
+						Required:    p.RequiredContentType,
+					}
+				}
+			}
+
+			if err == nil && expectedGroupVersion != "" {
+				if advertised, ok := readAdvertisedGroupVersion(resp); !ok || advertised != expectedGroupVersion {
+					err = &ErrGroupVersionNotAdvertised{Expected: expectedGroupVersion, Actual: advertised}
+				}
+			} else {
+				drainProbeResponseBody(resp)
+			}
+		}
+		errCh <- err
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+
+	case <-ctx.Done():
+		return ctx.Err()
+
+	// we had trouble with slow dial and DNS responses causing us to wait too long.
+	// we added this as insurance
+	case <-time.After(6 * time.Second):
+		return &ErrProbeTimeout{URL: discoveryURL}
+	}
+}
+
+// drainProbeResponseBody reads resp.Body to completion and closes it, transparently decoding a
+// gzip-encoded body first. The probe doesn't care about the body's contents, only that the
+// request succeeded, but fully draining it (rather than just closing it) lets the underlying
+// http.Transport reuse the connection for the next probe instead of opening a fresh one every
+// time, and decoding gzip first ensures draining doesn't fail on a compressed body we
+// explicitly asked for via Accept-Encoding.
+func drainProbeResponseBody(resp *http.Response) {
+	defer resp.Body.Close()
+
+	body := resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return
+		}
+		defer gzipReader.Close()
+		body = gzipReader
+	}
+	io.Copy(ioutil.Discard, body)
+}
+
+// readAdvertisedGroupVersion reads and closes resp.Body, transparently decoding a gzip-encoded
+// body the same way drainProbeResponseBody does, and looks for the top-level "groupVersion"
+// field a standard /apis/<group>/<version> discovery document (a metav1.APIResourceList) carries.
+// It returns ok=false if the body isn't valid JSON or doesn't have a non-empty groupVersion
+// field -- callers shouldn't distinguish those cases, since either way the expected group/version
+// wasn't advertised.
+func readAdvertisedGroupVersion(resp *http.Response) (groupVersion string, ok bool) {
+	defer resp.Body.Close()
+
+	body := resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return "", false
+		}
+		defer gzipReader.Close()
+		body = gzipReader
+	}
+
+	var doc struct {
+		GroupVersion string `json:"groupVersion"`
+	}
+	if err := json.NewDecoder(body).Decode(&doc); err != nil {
+		return "", false
+	}
+	return doc.GroupVersion, doc.GroupVersion != ""
+}
+
 type AvailableConditionController struct {
 	apiServiceClient apiregistrationclient.APIServicesGetter
 
@@ -62,15 +550,528 @@ type AvailableConditionController struct {
 	endpointsLister v1listers.EndpointsLister
 	endpointsSynced cache.InformerSynced
 
+	// namespaceLister is optional (set via SetNamespaceLister) and used to distinguish a
+	// backing Service that's merely missing from one whose namespace is terminating.
+	namespaceLister v1listers.NamespaceLister
+
 	discoveryClient *http.Client
 	serviceResolver ServiceResolver
+	prober          Prober
+
+	// localDelegateHealthChecker is optional (set via SetLocalDelegateHealthChecker) and, when
+	// configured, is called before declaring a local APIService (one with no Spec.Service)
+	// available. This lets callers catch a local handler that's still starting up instead of
+	// unconditionally trusting it the way we always have. A nil error means healthy.
+	localDelegateHealthChecker func() error
 
 	// To allow injection for testing.
 	syncFn func(key string) error
 
 	queue workqueue.RateLimitingInterface
+
+	// syncingAPIServices guards against running sync() for the same APIService key on two
+	// goroutines at once. The workqueue already avoids this for normal worker processing, but
+	// syncFn can also be invoked directly (e.g. in tests), so we keep an explicit belt-and-braces
+	// guard here too.
+	syncingAPIServices sync.Map
+
+	// MinReadyEndpoints is the number of ready endpoint addresses a backing Service must have
+	// before the APIService is considered available. Defaults to 1 (any address at all) when
+	// left at the zero value. Only consulted under EndpointReadinessPolicyRequireAny (the
+	// default) and EndpointReadinessPolicyRequireAll.
+	MinReadyEndpoints int
+
+	// EndpointReadinessPolicy controls how strictly a backing Service's Endpoints must be ready
+	// before its APIService is considered available. Defaults to
+	// EndpointReadinessPolicyRequireAny when left at the zero value, which is this controller's
+	// historical behavior.
+	EndpointReadinessPolicy EndpointReadinessPolicy
+
+	// TreatUnauthorizedAsAvailable, when true, makes a 401/403 from the discovery probe count
+	// as "available": the backend is clearly up and routable, it's just not letting the
+	// aggregator's probe credentials through. Defaults to false (a 401/403 still fails the probe).
+	TreatUnauthorizedAsAvailable bool
+
+	// ServiceNotFoundGracePeriod bounds how long after an APIService is first created a missing
+	// backing Service is reported with the softer AvailabilityReasonServiceNotFoundPending reason
+	// instead of AvailabilityReasonServiceNotFound. This covers cluster bootstrap, where the
+	// Service hasn't been created yet but is expected imminently, without immediately paging on
+	// what's actually still starting up. Defaults to zero (no grace period; every missing Service
+	// is reported as ServiceNotFound right away), which is this controller's historical behavior.
+	ServiceNotFoundGracePeriod time.Duration
+
+	// clock is used to stamp LastTransitionTime on condition changes. Defaults to the real
+	// clock; tests substitute a fake one to assert on timestamps deterministically.
+	clock clock.Clock
+
+	// transitionCh is optional (set via SetTransitionChannel) and, when configured, receives an
+	// APIServiceTransition every time an APIService's Available condition actually changes
+	// status, for consumers that want push notification instead of polling the lister. Sends are
+	// non-blocking: a slow or absent reader drops events rather than stalling sync().
+	transitionCh chan<- APIServiceTransition
+
+	// transitionHandlersMu guards transitionHandlers.
+	transitionHandlersMu sync.Mutex
+	// transitionHandlers are registered via AddTransitionHandler and invoked, each in its own
+	// goroutine, every time an APIService's Available condition actually changes status --
+	// alongside transitionCh, for consumers that would rather register a callback than read a
+	// channel.
+	transitionHandlers []func(old, new apiregistration.APIServiceCondition)
+
+	// LocalZone, when non-empty, is the failure zone this aggregator instance itself runs in.
+	// Set together with NodeZoneLookup to require that at least one ready endpoint address
+	// resolves to this zone, rather than being satisfied by ready addresses in a remote zone
+	// alone. Defaults to empty (zone-awareness disabled), which is this controller's historical
+	// behavior. Core v1.EndpointAddress carries no zone/topology field in this API version, so
+	// zone membership can only be derived by resolving each address's NodeName via
+	// NodeZoneLookup.
+	LocalZone string
+
+	// NodeZoneLookup resolves a Node name to the failure zone it runs in. Required for
+	// LocalZone to have any effect; an address whose NodeName is empty, or that NodeZoneLookup
+	// can't resolve, is treated as being of unknown zone and doesn't count toward either zone.
+	NodeZoneLookup func(nodeName string) (zone string, ok bool)
+
+	// PodTerminatingLookup reports whether the Pod identified by namespace and name is
+	// terminating. Optional; when set, it lets sync() distinguish a service that's draining
+	// during a rollout (every not-ready address backed by a terminating Pod) from one that's
+	// actually broken, reporting AvailabilityReasonEndpointsTerminating instead of the more
+	// generic AvailabilityReasonMissingEndpoints. Core v1.EndpointAddress carries no terminating
+	// field in this API version, so this can only be derived by resolving each address's
+	// TargetRef via PodTerminatingLookup. Defaults to nil (disabled), which is this controller's
+	// historical behavior.
+	PodTerminatingLookup func(namespace, name string) bool
+
+	// MinConsecutiveSuccesses is the number of consecutive successful syncs an APIService must
+	// accumulate before its Available condition is allowed to flip to True, to avoid flapping an
+	// APIService to available on a single lucky probe during a rollout. Defaults to 0, treated
+	// the same as 1 (every success counts immediately), which is this controller's historical
+	// behavior.
+	MinConsecutiveSuccesses int
+
+	// consecutiveSuccesses tracks, per APIService name, how many syncs in a row have reached the
+	// success path since the last one that didn't. Only consulted when MinConsecutiveSuccesses
+	// is greater than 1; reset whenever a sync reaches any other outcome.
+	consecutiveSuccesses sync.Map
+
+	// MaxConcurrentProbesPerHost caps how many discovery probes may be in flight at once against
+	// the same backing host, so APIServices that happen to share a Service (and therefore a
+	// host) don't overwhelm it with simultaneous connections. Defaults to 0 (unlimited), which is
+	// this controller's historical behavior.
+	MaxConcurrentProbesPerHost int
+
+	// probeSemaphoresMu guards probeSemaphores.
+	probeSemaphoresMu sync.Mutex
+	// probeSemaphores holds a counting semaphore per host, lazily created, used to enforce
+	// MaxConcurrentProbesPerHost. Only consulted when MaxConcurrentProbesPerHost is greater
+	// than 0.
+	probeSemaphores map[string]chan struct{}
+
+	// CircuitBreakerThreshold, when greater than 0, trips a per-backend-host circuit breaker after
+	// this many consecutive probe failures against that host, short-circuiting further probes
+	// against it (reporting AvailabilityReasonCircuitBreakerOpen and the last known failure) for
+	// CircuitBreakerCooldown instead of repeatedly probing a backend that's already known to be
+	// down. After the cooldown, the breaker half-opens to let a single trial probe through; a
+	// successful trial closes it, a failed one re-opens it for another cooldown. Defaults to 0
+	// (disabled), which is this controller's historical behavior.
+	CircuitBreakerThreshold int
+
+	// CircuitBreakerCooldown is how long a tripped circuit breaker stays open before half-opening
+	// to allow a trial probe through. Only consulted when CircuitBreakerThreshold is greater than
+	// 0.
+	CircuitBreakerCooldown time.Duration
+
+	// circuitBreakersMu guards circuitBreakers.
+	circuitBreakersMu sync.Mutex
+	// circuitBreakers holds a circuitBreaker per backend host, lazily created, used to enforce
+	// CircuitBreakerThreshold/CircuitBreakerCooldown. Only consulted when CircuitBreakerThreshold
+	// is greater than 0.
+	circuitBreakers map[string]*circuitBreaker
+
+	// ValidateAdvertisedGroupVersion, when true, requires the discovery document returned by an
+	// otherwise-successful probe to advertise this APIService's own "group/version", reporting
+	// AvailabilityReasonGroupVersionNotAdvertised when it doesn't -- catching a Service that
+	// resolves and responds but is silently routing discovery requests to the wrong backend.
+	// Defaults to false, which is this controller's historical behavior.
+	ValidateAdvertisedGroupVersion bool
+
+	// pauseMu guards paused and is the lock pauseCond is built on.
+	pauseMu sync.Mutex
+	// paused, when true, makes workers block instead of pulling keys off the queue. Keys are
+	// still enqueued normally while paused (by the informer event handlers, AddRateLimited
+	// retries, etc.) and accumulate until Resume is called; this is for coordinating with a
+	// controlled rollout, not for stopping the controller (use the Run stopCh for that).
+	paused bool
+	// pauseCond is signaled by Resume to wake workers blocked waiting out a pause.
+	pauseCond *sync.Cond
+}
+
+// APIServiceTransition describes an APIService's Available condition changing status. It's sent
+// on the channel configured via SetTransitionChannel.
+type APIServiceTransition struct {
+	Name      string
+	OldStatus apiregistration.ConditionStatus
+	NewStatus apiregistration.ConditionStatus
+}
+
+// clockOrDefault returns the configured clock, falling back to the real clock when none has
+// been set.
+func (c *AvailableConditionController) clockOrDefault() clock.Clock {
+	if c.clock == nil {
+		return clock.RealClock{}
+	}
+	return c.clock
+}
+
+// SetClock overrides the clock used to stamp LastTransitionTime. It exists for tests; production
+// callers should leave this unset to get the real clock.
+func (c *AvailableConditionController) SetClock(clock clock.Clock) {
+	c.clock = clock
+}
+
+// apiServicePort is the only port an APIService's backing Service is ever resolved against; see
+// the Service field's doc comment on APIServiceSpec ("It must communicate on port 443").
+const apiServicePort = 443
+
+// tlsConfigForAPIService returns the TLS configuration the discovery probe should use for an
+// APIService with the given spec. When InsecureSkipTLSVerify is set, or no CABundle is
+// configured to validate against, the probe skips certificate verification entirely -- this
+// matches the controller's long-standing behavior of trusting the network for status checks.
+// Otherwise, the serving certificate is validated against CABundle like any other client would.
+func tlsConfigForAPIService(spec apiregistration.APIServiceSpec) (*tls.Config, error) {
+	if spec.InsecureSkipTLSVerify || len(spec.CABundle) == 0 {
+		return &tls.Config{InsecureSkipVerify: true}, nil
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(spec.CABundle) {
+		return nil, fmt.Errorf("unable to parse CABundle")
+	}
+	return &tls.Config{RootCAs: pool}, nil
+}
+
+// probeURL validates a URL resolved by a ServiceResolver for use as a discovery probe target and
+// returns its string form. It exists to pull probe URL validation out of sync() so it can be
+// tested in isolation: a ServiceResolver that returns a URL with no Host (for example, a custom
+// implementation with a bug in its endpoint-selection logic) would otherwise produce a
+// malformed-looking probe target like "https:///apis" instead of a clear, actionable error.
+func probeURL(resolved *url.URL, apiService *apiregistration.APIService) (string, error) {
+	if resolved == nil || resolved.Host == "" {
+		return "", fmt.Errorf("resolved discovery endpoint for APIService %q has no host: %v", apiService.Name, resolved)
+	}
+	return resolved.String(), nil
+}
+
+// rootDNSError unwraps err looking for a *net.DNSError, following the *url.Error and
+// *net.OpError wrapping that the net/http client applies to errors from the dial. It returns
+// false if no DNS error is found anywhere in the chain.
+func rootDNSError(err error) (*net.DNSError, bool) {
+	for err != nil {
+		switch e := err.(type) {
+		case *net.DNSError:
+			return e, true
+		case *url.Error:
+			err = e.Err
+		case *net.OpError:
+			err = e.Err
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// rootHTTP2Error unwraps err looking for an HTTP/2-specific error -- a GOAWAY, stream, or
+// connection error raised by the http2 transport -- following the *url.Error wrapping that the
+// net/http client applies. It returns false if no HTTP/2 error is found, which covers both
+// non-HTTP/2 failures and a nil err.
+func rootHTTP2Error(err error) (error, bool) {
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+	switch err.(type) {
+	case http2.GoAwayError, http2.StreamError, http2.ConnectionError:
+		return err, true
+	default:
+		return nil, false
+	}
+}
+
+// rootConnectionRefusedError unwraps err looking for a *net.OpError reporting ECONNREFUSED,
+// following the *url.Error wrapping that the net/http client applies. It returns false if no
+// connection-refused error is found anywhere in the chain. This is distinguished from a bare
+// timeout because "nothing is listening" and "the backend is slow or hung" call for different
+// remediations.
+func rootConnectionRefusedError(err error) (*net.OpError, bool) {
+	for err != nil {
+		switch e := err.(type) {
+		case *url.Error:
+			err = e.Err
+		case *net.OpError:
+			if isConnectionRefused(e) {
+				return e, true
+			}
+			err = e.Err
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// isConnectionRefused reports whether e wraps the ECONNREFUSED errno, however the platform's
+// net package happens to have packaged it (a bare syscall.Errno, or one wrapped in an
+// *os.SyscallError).
+func isConnectionRefused(e *net.OpError) bool {
+	switch inner := e.Err.(type) {
+	case *os.SyscallError:
+		errno, ok := inner.Err.(syscall.Errno)
+		return ok && errno == syscall.ECONNREFUSED
+	case syscall.Errno:
+		return inner == syscall.ECONNREFUSED
+	}
+	return false
+}
+
+// isProbeTimeout reports whether err represents a probe that didn't get a response in time,
+// whether that's our own *ErrProbeTimeout (the select-based deadline in clientProber.Probe) or a
+// timeout surfaced by the underlying http.Client or dialer (which satisfies the standard
+// net.Error interface, including when wrapped in a *url.Error -- url.Error.Timeout() delegates to
+// the wrapped error).
+func isProbeTimeout(err error) bool {
+	if _, ok := err.(*ErrProbeTimeout); ok {
+		return true
+	}
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+// namespaceTerminating reports whether namespace is known to be in the process of being
+// deleted. It returns false whenever no NamespaceLister has been configured.
+func (c *AvailableConditionController) namespaceTerminating(namespace string) bool {
+	if c.namespaceLister == nil {
+		return false
+	}
+	ns, err := c.namespaceLister.Get(namespace)
+	if err != nil {
+		return false
+	}
+	return ns.Status.Phase == v1.NamespaceTerminating
+}
+
+// UnavailableAPIServices returns every APIService whose Available condition is not currently
+// True, as seen by this controller's local cache. An APIService with no Available condition at
+// all (for example, one that hasn't been synced yet) counts as unavailable.
+func (c *AvailableConditionController) UnavailableAPIServices() ([]*apiregistration.APIService, error) {
+	apiServices, err := c.apiServiceLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	unavailable := make([]*apiregistration.APIService, 0, len(apiServices))
+	for _, apiService := range apiServices {
+		if !apiregistration.IsAPIServiceConditionTrue(apiService, apiregistration.Available) {
+			unavailable = append(unavailable, apiService)
+		}
+	}
+	return unavailable, nil
+}
+
+// StaleAPIServices returns every APIService whose Available condition's LastTransitionTime is
+// older than maxAge, as seen by this controller's local cache. LastTransitionTime only moves when
+// the condition's Status changes, so the result includes an APIService that hasn't been
+// reconciled recently and equally one whose status has simply stayed the same for longer than
+// maxAge; the time of the last successful sync is not tracked. An APIService with no Available
+// condition at all counts as stale.
+func (c *AvailableConditionController) StaleAPIServices(maxAge time.Duration) ([]*apiregistration.APIService, error) {
+	apiServices, err := c.apiServiceLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.clockOrDefault().Now()
+	stale := make([]*apiregistration.APIService, 0, len(apiServices))
+	for _, apiService := range apiServices {
+		condition := apiregistration.GetAPIServiceConditionByType(apiService, apiregistration.Available)
+		if condition == nil || now.Sub(condition.LastTransitionTime.Time) > maxAge {
+			stale = append(stale, apiService)
+		}
+	}
+	return stale, nil
+}
+
+// APIServiceAvailabilitySnapshot is one APIService's availability as captured by SnapshotJSON.
+type APIServiceAvailabilitySnapshot struct {
+	Name               string      `json:"name"`
+	Available          bool        `json:"available"`
+	Reason             string      `json:"reason"`
+	Message            string      `json:"message"`
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// SnapshotJSON returns a JSON array of every known APIService's current Available condition, as
+// seen by this controller's local cache, for support engineers to attach a one-shot diagnostic
+// dump to a ticket without having to run `kubectl get apiservices` and cross-reference conditions
+// by hand. An APIService with no Available condition yet is included with Available false and an
+// empty Reason/Message.
+func (c *AvailableConditionController) SnapshotJSON() ([]byte, error) {
+	apiServices, err := c.apiServiceLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make([]APIServiceAvailabilitySnapshot, 0, len(apiServices))
+	for _, apiService := range apiServices {
+		entry := APIServiceAvailabilitySnapshot{Name: apiService.Name}
+		if condition := apiregistration.GetAPIServiceConditionByType(apiService, apiregistration.Available); condition != nil {
+			entry.Available = condition.Status == apiregistration.ConditionTrue
+			entry.Reason = condition.Reason
+			entry.Message = condition.Message
+			entry.LastTransitionTime = condition.LastTransitionTime
+		}
+		snapshot = append(snapshot, entry)
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Name < snapshot[j].Name })
+
+	return json.Marshal(snapshot)
+}
+
+// minReadyEndpoints returns the effective minimum-ready-endpoints threshold, defaulting to 1.
+func (c *AvailableConditionController) minReadyEndpoints() int {
+	if c.MinReadyEndpoints <= 0 {
+		return 1
+	}
+	return c.MinReadyEndpoints
+}
+
+// EndpointReadinessPolicy controls how strictly a backing Service's Endpoints must be ready
+// before its APIService is considered available. A Service with hundreds of endpoint addresses
+// makes "every single one must be ready" impractical, so operators can relax the requirement
+// instead of probing (or counting) them all.
+type EndpointReadinessPolicy string
+
+const (
+	// EndpointReadinessPolicyRequireAny is satisfied once at least MinReadyEndpoints addresses
+	// are ready, regardless of how many other addresses are not. This is the default, matching
+	// the controller's historical behavior.
+	EndpointReadinessPolicyRequireAny EndpointReadinessPolicy = "RequireAny"
+	// EndpointReadinessPolicyRequireMajority requires a strict majority of all known addresses
+	// (ready and not-ready combined) to be ready.
+	EndpointReadinessPolicyRequireMajority EndpointReadinessPolicy = "RequireMajority"
+	// EndpointReadinessPolicyRequireAll requires every known address to be ready, and at least
+	// MinReadyEndpoints of them to exist at all.
+	EndpointReadinessPolicyRequireAll EndpointReadinessPolicy = "RequireAll"
+)
+
+// endpointReadinessPolicy returns c.EndpointReadinessPolicy, or EndpointReadinessPolicyRequireAny
+// when unset.
+func (c *AvailableConditionController) endpointReadinessPolicy() EndpointReadinessPolicy {
+	if c.EndpointReadinessPolicy == "" {
+		return EndpointReadinessPolicyRequireAny
+	}
+	return c.EndpointReadinessPolicy
+}
+
+// evaluateEndpointReadiness applies c's EndpointReadinessPolicy to the given ready and not-ready
+// address counts for service. ok is false when the policy isn't satisfied, in which case reason
+// and message explain why in terms specific to the policy that failed.
+func (c *AvailableConditionController) evaluateEndpointReadiness(readyAddresses, notReadyAddresses int, service *apiregistration.ServiceReference) (reason, message string, ok bool) {
+	total := readyAddresses + notReadyAddresses
+	switch c.endpointReadinessPolicy() {
+	case EndpointReadinessPolicyRequireAll:
+		if total == 0 || notReadyAddresses > 0 || readyAddresses < c.minReadyEndpoints() {
+			return string(apiregistration.AvailabilityReasonEndpointsNotAllReady),
+				fmt.Sprintf("endpoints for service/%s in %q are not all ready (%d/%d ready)", service.Name, service.Namespace, readyAddresses, total),
+				false
+		}
+	case EndpointReadinessPolicyRequireMajority:
+		if total == 0 || readyAddresses*2 <= total {
+			return string(apiregistration.AvailabilityReasonEndpointsNotMajorityReady),
+				fmt.Sprintf("endpoints for service/%s in %q do not have a ready majority (%d/%d ready)", service.Name, service.Namespace, readyAddresses, total),
+				false
+		}
+	default:
+		if readyAddresses < c.minReadyEndpoints() {
+			return string(apiregistration.AvailabilityReasonMissingEndpoints),
+				fmt.Sprintf("endpoints for service/%s in %q have no addresses", service.Name, service.Namespace),
+				false
+		}
+	}
+	return "", "", true
+}
+
+// hasReadyPortMatch reports whether endpoints has at least one ready address in a subset that
+// serves one of servicePorts, by comparing port numbers. A subset with no ready addresses is
+// skipped, since its ports don't back anything that's actually reachable yet.
+func hasReadyPortMatch(endpoints *v1.Endpoints, servicePorts []v1.ServicePort) bool {
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) == 0 {
+			continue
+		}
+		for _, endpointPort := range subset.Ports {
+			for _, servicePort := range servicePorts {
+				if endpointPort.Port == servicePort.Port {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// servicePortNumbers renders servicePorts' port numbers for use in a status message.
+func servicePortNumbers(servicePorts []v1.ServicePort) []string {
+	numbers := make([]string, 0, len(servicePorts))
+	for _, servicePort := range servicePorts {
+		numbers = append(numbers, strconv.Itoa(int(servicePort.Port)))
+	}
+	return numbers
+}
+
+// hasLocalZoneReadyAddress reports whether endpoints has at least one ready address whose Node
+// resolves, via c.NodeZoneLookup, to c.LocalZone. An address with no NodeName, or one
+// NodeZoneLookup can't resolve, doesn't count toward either zone.
+func (c *AvailableConditionController) hasLocalZoneReadyAddress(endpoints *v1.Endpoints) bool {
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			if address.NodeName == nil {
+				continue
+			}
+			if zone, ok := c.NodeZoneLookup(*address.NodeName); ok && zone == c.LocalZone {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allNotReadyAddressesTerminating reports whether every not-ready address across endpoints'
+// subsets is backed by a Pod that c.PodTerminatingLookup identifies as terminating. An address
+// with no Pod TargetRef counts as not terminating, since there's nothing to resolve. Returns
+// false for endpoints with no not-ready addresses at all, since "all of zero" isn't a meaningful
+// signal here.
+func (c *AvailableConditionController) allNotReadyAddressesTerminating(endpoints *v1.Endpoints) bool {
+	found := false
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.NotReadyAddresses {
+			found = true
+			if address.TargetRef == nil || address.TargetRef.Kind != "Pod" {
+				return false
+			}
+			if !c.PodTerminatingLookup(address.TargetRef.Namespace, address.TargetRef.Name) {
+				return false
+			}
+		}
+	}
+	return found
 }
 
+// NewAvailableConditionController is the exported constructor for AvailableConditionController.
+// It wires the controller's listers from the given informers and requires every dependency
+// production callers need, so a caller can't forget to set one up the way ad hoc field
+// assignment would allow. Unit tests that need to inject fakes (e.g. a fake Prober or an
+// indexer-backed lister with pre-seeded state) still build the struct by literal field
+// assignment instead, since the informers this constructor takes always come from a real or
+// fake clientset's shared informer factory.
 func NewAvailableConditionController(
 	apiServiceInformer informers.APIServiceInformer,
 	serviceInformer v1informers.ServiceInformer,
@@ -90,20 +1091,26 @@ func NewAvailableConditionController(
 		serviceResolver:  serviceResolver,
 		queue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "AvailableConditionController"),
 	}
+	c.pauseCond = sync.NewCond(&c.pauseMu)
 
 	// construct an http client that will ignore TLS verification (if someone owns the network and messes with your status
-	// that's not so bad) and sets a very short timeout.
+	// that's not so bad) and sets a very short timeout. Prefer HTTP/2, matching how the
+	// aggregator proxies discovery traffic to these same backends.
 	discoveryClient := &http.Client{
-		Transport: &http.Transport{
+		Transport: utilnet.SetTransportDefaults(&http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
+		}),
 		// the request should happen quickly.
 		Timeout: 5 * time.Second,
+		// don't follow a backend's redirect (e.g. to a login page) into an unrelated endpoint;
+		// report it as ErrProbeRedirect instead.
+		CheckRedirect: noFollowRedirects,
 	}
 	if proxyTransport != nil {
 		//discoveryClient.Transport = proxyTransport
 	}
 	c.discoveryClient = discoveryClient
+	c.prober = &clientProber{discoveryClient: discoveryClient}
 
 	// resync on this one because it is low cardinality and rechecking the actual discovery
 	// allows us to detect health in a more timely fashion when network connectivity to
@@ -134,7 +1141,220 @@ func NewAvailableConditionController(
 	return c
 }
 
+// updateAvailableCondition sets apiService's Available condition to newCondition and persists
+// the change, unless apiService already has that exact condition (ignoring LastTransitionTime),
+// in which case it does nothing. This avoids a spurious UpdateStatus call -- and the watch event
+// it would trigger on every controller -- on each resync of an APIService whose availability
+// hasn't actually changed.
+//
+// The write is wrapped in retry.RetryOnConflict: other writers (the APIService's owner reapplying
+// its spec, another sync racing on the same APIService) can update it between when sync read its
+// cached copy and when this UpdateStatus lands, producing a 409 Conflict. Rather than surfacing
+// that as a sync error and waiting for the next resync, each retry refetches the latest version
+// and reapplies newCondition on top of it before trying again.
+func (c *AvailableConditionController) updateAvailableCondition(apiService *apiregistration.APIService, newCondition apiregistration.APIServiceCondition) error {
+	if newCondition.Type == apiregistration.Available {
+		c.consecutiveSuccesses.Delete(apiService.Name)
+		recordAvailabilityReason(newCondition.Reason)
+	}
+	existing := apiregistration.GetAPIServiceConditionByType(apiService, newCondition.Type)
+	if existing != nil && existing.Equal(newCondition) {
+		return nil
+	}
+	if existing != nil {
+		// SetAPIServiceCondition below updates apiService's condition in place; keep a copy of the
+		// previous one for notifyTransition.
+		previous := *existing
+		existing = &previous
+	}
+	glog.V(4).Infof("Setting Available condition for APIService %q: reason=%s message=%q", apiService.Name, newCondition.Reason, newCondition.Message)
+
+	attempt := apiService
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		apiregistration.SetAPIServiceCondition(attempt, newCondition)
+		if newCondition.Type == apiregistration.Available {
+			recordAvailabilityTransition(attempt, newCondition.Reason, newCondition.LastTransitionTime)
+		}
+		updated, updateErr := c.apiServiceClient.APIServices().UpdateStatus(attempt)
+		if apierrors.IsConflict(updateErr) {
+			// someone else wrote this APIService between our read and our write -- refetch and
+			// reapply newCondition on top of their version instead of retrying with our now-stale
+			// one, so we don't blindly clobber their change.
+			latest, getErr := c.apiServiceClient.APIServices().Get(apiService.Name, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+			attempt = latest
+			return updateErr
+		}
+		if updateErr == nil {
+			attempt = updated
+		}
+		return updateErr
+	})
+	if err == nil {
+		c.notifyTransition(apiService.Name, existing, newCondition)
+		recordAvailability(apiService.Name, newCondition.Status)
+	}
+	return err
+}
+
+// notifyTransition sends an APIServiceTransition on c.transitionCh, and invokes every handler
+// registered via AddTransitionHandler, when newCondition's Status differs from existing's
+// (existing is nil when the APIService had no prior Available condition at all, treated as
+// ConditionUnknown). The channel send is a no-op when no channel has been configured, and never
+// blocks sync() waiting for a reader; handlers are invoked even if no channel is configured.
+func (c *AvailableConditionController) notifyTransition(name string, existing *apiregistration.APIServiceCondition, newCondition apiregistration.APIServiceCondition) {
+	oldStatus := apiregistration.ConditionUnknown
+	oldCondition := apiregistration.APIServiceCondition{Type: newCondition.Type, Status: apiregistration.ConditionUnknown}
+	if existing != nil {
+		oldStatus = existing.Status
+		oldCondition = *existing
+	}
+	if oldStatus == newCondition.Status {
+		return
+	}
+	if c.transitionCh != nil {
+		select {
+		case c.transitionCh <- APIServiceTransition{Name: name, OldStatus: oldStatus, NewStatus: newCondition.Status}:
+		default:
+		}
+	}
+	c.invokeTransitionHandlers(oldCondition, newCondition)
+}
+
+// invokeTransitionHandlers calls every handler registered via AddTransitionHandler in its own
+// goroutine, recovering any panic so a misbehaving handler can't take down sync().
+func (c *AvailableConditionController) invokeTransitionHandlers(old, newCondition apiregistration.APIServiceCondition) {
+	c.transitionHandlersMu.Lock()
+	handlers := append([]func(old, new apiregistration.APIServiceCondition){}, c.transitionHandlers...)
+	c.transitionHandlersMu.Unlock()
+
+	for _, handler := range handlers {
+		go func(handler func(old, new apiregistration.APIServiceCondition)) {
+			defer func() {
+				if r := recover(); r != nil {
+					glog.Errorf("APIService transition handler panicked: %v", r)
+				}
+			}()
+			handler(old, newCondition)
+		}(handler)
+	}
+}
+
+// AddTransitionHandler registers handler to be invoked, in its own goroutine with panic recovery,
+// every time an APIService's Available condition actually changes status -- alongside any
+// previously registered handlers and the channel configured via SetTransitionChannel. Safe to
+// call from multiple goroutines.
+func (c *AvailableConditionController) AddTransitionHandler(handler func(old, new apiregistration.APIServiceCondition)) {
+	c.transitionHandlersMu.Lock()
+	defer c.transitionHandlersMu.Unlock()
+	c.transitionHandlers = append(c.transitionHandlers, handler)
+}
+
+// updateAvailableAndDegradedConditions sets apiService's Available and Degraded conditions and
+// persists both changes with a single UpdateStatus call (retried on conflict, like
+// updateAvailableCondition), skipping the write entirely if neither condition actually changed
+// (ignoring LastTransitionTime). Degraded is only meaningful once Available is already True -- a
+// service that's down isn't usefully described as "degraded" on top of that -- so this is only
+// called from the success path in sync(), unlike the plain Available-only updateAvailableCondition
+// used everywhere else.
+func (c *AvailableConditionController) updateAvailableAndDegradedConditions(apiService *apiregistration.APIService, available, degraded apiregistration.APIServiceCondition) error {
+	if c.MinConsecutiveSuccesses > 1 {
+		successes := 1
+		if v, ok := c.consecutiveSuccesses.Load(apiService.Name); ok {
+			successes = v.(int) + 1
+		}
+		c.consecutiveSuccesses.Store(apiService.Name, successes)
+		if successes < c.MinConsecutiveSuccesses {
+			available.Status = apiregistration.ConditionFalse
+			available.Reason = string(apiregistration.AvailabilityReasonAwaitingConsecutiveSuccesses)
+			available.Message = fmt.Sprintf("passed %d/%d consecutive checks required before being marked available", successes, c.MinConsecutiveSuccesses)
+		}
+	} else {
+		c.consecutiveSuccesses.Delete(apiService.Name)
+	}
+	recordAvailabilityReason(available.Reason)
+
+	existingAvailable := apiregistration.GetAPIServiceConditionByType(apiService, available.Type)
+	existingDegraded := apiregistration.GetAPIServiceConditionByType(apiService, degraded.Type)
+	availableChanged := existingAvailable == nil || !existingAvailable.Equal(available)
+	degradedChanged := existingDegraded == nil || !existingDegraded.Equal(degraded)
+	if !availableChanged && !degradedChanged {
+		return nil
+	}
+	if existingAvailable != nil {
+		// SetAPIServiceCondition below updates apiService's condition in place; keep a copy of the
+		// previous one for notifyTransition.
+		previous := *existingAvailable
+		existingAvailable = &previous
+	}
+
+	if availableChanged {
+		glog.V(4).Infof("Setting Available condition for APIService %q: reason=%s message=%q", apiService.Name, available.Reason, available.Message)
+	}
+	if degradedChanged {
+		glog.V(4).Infof("Setting Degraded condition for APIService %q: reason=%s message=%q", apiService.Name, degraded.Reason, degraded.Message)
+	}
+
+	attempt := apiService
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		apiregistration.SetAPIServiceCondition(attempt, available)
+		if availableChanged {
+			recordAvailabilityTransition(attempt, available.Reason, available.LastTransitionTime)
+		}
+		apiregistration.SetAPIServiceCondition(attempt, degraded)
+		updated, updateErr := c.apiServiceClient.APIServices().UpdateStatus(attempt)
+		if apierrors.IsConflict(updateErr) {
+			// someone else wrote this APIService between our read and our write -- refetch and
+			// reapply both conditions on top of their version instead of retrying with our now-stale
+			// one, so we don't blindly clobber their change.
+			latest, getErr := c.apiServiceClient.APIServices().Get(apiService.Name, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+			attempt = latest
+			return updateErr
+		}
+		if updateErr == nil {
+			attempt = updated
+		}
+		return updateErr
+	})
+	if err == nil {
+		if availableChanged {
+			c.notifyTransition(apiService.Name, existingAvailable, available)
+		}
+		recordAvailability(apiService.Name, available.Status)
+	}
+	return err
+}
+
+// SetTransitionChannel configures an optional channel that receives an APIServiceTransition every
+// time an APIService's Available condition changes status. Sends are non-blocking, so callers
+// should size the channel (or drain it promptly) according to how many transitions they expect to
+// miss being acceptable.
+func (c *AvailableConditionController) SetTransitionChannel(ch chan<- APIServiceTransition) {
+	c.transitionCh = ch
+}
+
+// sync is a thin wrapper around syncWithContext using context.Background(), for the common case
+// where no caller-supplied deadline or tracing span needs to be threaded through.
 func (c *AvailableConditionController) sync(key string) error {
+	return c.syncWithContext(context.Background(), key)
+}
+
+// syncWithContext is sync, but takes a context that's threaded through to the discovery probe
+// (when the configured Prober also implements ContextProber) so callers can cancel a reconcile
+// or attach a tracing span around it. The generated APIServicesGetter this controller uses has
+// no context-aware UpdateStatus in this API version, so a canceled ctx can still abort a
+// probe that's already in flight, but can't also abort a status update that's already started.
+func (c *AvailableConditionController) syncWithContext(ctx context.Context, key string) error {
+	if _, alreadySyncing := c.syncingAPIServices.LoadOrStore(key, struct{}{}); alreadySyncing {
+		return fmt.Errorf("sync already in progress for %q", key)
+	}
+	defer c.syncingAPIServices.Delete(key)
+
 	inAPIService, err := c.apiServiceLister.Get(key)
 	if apierrors.IsNotFound(err) {
 		return nil
@@ -144,115 +1364,383 @@ func (c *AvailableConditionController) sync(key string) error {
 	}
 
 	apiService := inAPIService.DeepCopy()
+	glog.V(4).Infof("Syncing APIService %q", apiService.Name)
 
 	availableCondition := apiregistration.APIServiceCondition{
 		Type:               apiregistration.Available,
 		Status:             apiregistration.ConditionTrue,
-		LastTransitionTime: metav1.Now(),
+		LastTransitionTime: metav1.NewTime(c.clockOrDefault().Now()),
+	}
+
+	// allow availability checks to be bypassed entirely via annotation
+	if apiService.Annotations[apiregistration.DisableAvailabilityCheckAnnotation] == "true" {
+		availableCondition.Reason = string(apiregistration.AvailabilityReasonCheckSkipped)
+		availableCondition.Message = fmt.Sprintf("availability checks are disabled by the %q annotation", apiregistration.DisableAvailabilityCheckAnnotation)
+		return c.updateAvailableCondition(apiService, availableCondition)
 	}
 
-	// local API services are always considered available
+	// local API services are considered available unless a readiness hook says otherwise
 	if apiService.Spec.Service == nil {
-		apiregistration.SetAPIServiceCondition(apiService, apiregistration.NewLocalAvailableAPIServiceCondition())
-		_, err := c.apiServiceClient.APIServices().UpdateStatus(apiService)
-		return err
+		if c.localDelegateHealthChecker != nil {
+			if healthErr := c.localDelegateHealthChecker(); healthErr != nil {
+				availableCondition.Status = apiregistration.ConditionFalse
+				availableCondition.Reason = string(apiregistration.AvailabilityReasonLocalHealthCheckFailed)
+				availableCondition.Message = fmt.Sprintf("local APIService health check failed: %v", healthErr)
+				return c.updateAvailableCondition(apiService, availableCondition)
+			}
+		}
+		localCondition := apiregistration.NewLocalAvailableAPIServiceCondition()
+		localCondition.LastTransitionTime = metav1.NewTime(c.clockOrDefault().Now())
+		return c.updateAvailableCondition(apiService, localCondition)
 	}
 
-	service, err := c.serviceLister.Services(apiService.Spec.Service.Namespace).Get(apiService.Spec.Service.Name)
-	if apierrors.IsNotFound(err) {
+	// an APIService with no group/version can never be meaningfully probed; report that plainly
+	// instead of proceeding to a discovery failure that wouldn't explain the real problem
+	if apiService.Spec.Group == "" || apiService.Spec.Version == "" {
 		availableCondition.Status = apiregistration.ConditionFalse
-		availableCondition.Reason = "ServiceNotFound"
-		availableCondition.Message = fmt.Sprintf("service/%s in %q is not present", apiService.Spec.Service.Name, apiService.Spec.Service.Namespace)
-		apiregistration.SetAPIServiceCondition(apiService, availableCondition)
-		_, err := c.apiServiceClient.APIServices().UpdateStatus(apiService)
-		return err
+		availableCondition.Reason = string(apiregistration.AvailabilityReasonMissingGroupVersion)
+		availableCondition.Message = fmt.Sprintf("apiservice/%s has an empty group/version (%q/%q) and cannot be probed", apiService.Name, apiService.Spec.Group, apiService.Spec.Version)
+		return c.updateAvailableCondition(apiService, availableCondition)
+	}
+
+	available, degraded, hasDegraded, probeErr := c.probeBackend(ctx, apiService, apiService.Spec.Service)
+	if available.Status != apiregistration.ConditionTrue {
+		primaryReason, primaryMessage := available.Reason, available.Message
+		for i := range apiService.Spec.FallbackServices {
+			fallbackRef := apiService.Spec.FallbackServices[i]
+			fallbackAvailable, fallbackDegraded, fallbackHasDegraded, _ := c.probeBackend(ctx, apiService, &fallbackRef)
+			if fallbackAvailable.Status != apiregistration.ConditionTrue {
+				continue
+			}
+			fallbackAvailable.Message = fmt.Sprintf("primary service/%s in %q is unavailable (%s: %s); using healthy fallback service/%s in %q instead: %s",
+				apiService.Spec.Service.Name, apiService.Spec.Service.Namespace, primaryReason, primaryMessage,
+				fallbackRef.Name, fallbackRef.Namespace, fallbackAvailable.Message)
+			available, degraded, hasDegraded, probeErr = fallbackAvailable, fallbackDegraded, fallbackHasDegraded, nil
+			break
+		}
+	}
+
+	if hasDegraded {
+		if updateErr := c.updateAvailableAndDegradedConditions(apiService, available, degraded); updateErr != nil {
+			return updateErr
+		}
+	} else {
+		if updateErr := c.updateAvailableCondition(apiService, available); updateErr != nil {
+			return updateErr
+		}
+	}
+	// force a requeue on a discovery-stage failure, same as always -- probeErr is nil for every
+	// other kind of failure, and for a fallback service that resolved the primary's failure.
+	return probeErr
+}
+
+// probeBackend resolves ref as an APIService's backing Service, checks its endpoint readiness
+// (for a ClusterIP Service, unless the APIService opts out via CrossClusterServiceAnnotation), and
+// probes its discovery endpoint, returning the Available condition describing the outcome. When
+// ref is fully healthy, it also returns the Degraded condition reflecting endpoint readiness,
+// with hasDegraded true -- callers shouldn't persist a Degraded condition computed for a backend
+// that turned out not to be the one actually serving traffic. retryErr is non-nil only when the
+// failure came from the discovery probe stage itself (as opposed to, say, a missing Service),
+// mirroring sync's long-standing behavior of forcing a requeue specifically for those.
+//
+// syncWithContext calls this once for apiService.Spec.Service, the primary backend, and again for
+// each of apiService.Spec.FallbackServices in order if the primary isn't available, reporting
+// Available as True from the first one that is.
+func (c *AvailableConditionController) probeBackend(ctx context.Context, apiService *apiregistration.APIService, ref *apiregistration.ServiceReference) (available, degraded apiregistration.APIServiceCondition, hasDegraded bool, retryErr error) {
+	available = apiregistration.APIServiceCondition{
+		Type:               apiregistration.Available,
+		Status:             apiregistration.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(c.clockOrDefault().Now()),
+	}
+
+	service, err := c.serviceLister.Services(ref.Namespace).Get(ref.Name)
+	if apierrors.IsNotFound(err) {
+		available.Status = apiregistration.ConditionFalse
+		if c.namespaceTerminating(ref.Namespace) {
+			available.Reason = string(apiregistration.AvailabilityReasonNamespaceTerminating)
+			available.Message = fmt.Sprintf("service/%s is not present because namespace %q is terminating", ref.Name, ref.Namespace)
+		} else if age := c.clockOrDefault().Now().Sub(apiService.CreationTimestamp.Time); c.ServiceNotFoundGracePeriod > 0 && age < c.ServiceNotFoundGracePeriod {
+			available.Reason = string(apiregistration.AvailabilityReasonServiceNotFoundPending)
+			available.Message = fmt.Sprintf("service/%s in %q is not present, but the APIService is still within its %v creation grace period", ref.Name, ref.Namespace, c.ServiceNotFoundGracePeriod)
+		} else {
+			available.Reason = string(apiregistration.AvailabilityReasonServiceNotFound)
+			available.Message = fmt.Sprintf("service/%s in %q is not present", ref.Name, ref.Namespace)
+		}
+		return available, degraded, false, nil
 	} else if err != nil {
-		availableCondition.Status = apiregistration.ConditionUnknown
-		availableCondition.Reason = "ServiceAccessError"
-		availableCondition.Message = fmt.Sprintf("service/%s in %q cannot be checked due to: %v", apiService.Spec.Service.Name, apiService.Spec.Service.Namespace, err)
-		apiregistration.SetAPIServiceCondition(apiService, availableCondition)
-		_, err := c.apiServiceClient.APIServices().UpdateStatus(apiService)
-		return err
+		available.Status = apiregistration.ConditionUnknown
+		available.Reason = string(apiregistration.AvailabilityReasonServiceAccessError)
+		available.Message = fmt.Sprintf("service/%s in %q cannot be checked due to: %v", ref.Name, ref.Namespace, err)
+		return available, degraded, false, nil
 	}
 
-	if service.Spec.Type == v1.ServiceTypeClusterIP {
-		endpoints, err := c.endpointsLister.Endpoints(apiService.Spec.Service.Namespace).Get(apiService.Spec.Service.Name)
+	readyAddresses := 0
+	notReadyAddresses := 0
+	crossClusterService := apiService.Annotations[apiregistration.CrossClusterServiceAnnotation] == "true"
+	if service.Spec.Type == v1.ServiceTypeClusterIP && !crossClusterService {
+		endpoints, err := c.endpointsLister.Endpoints(ref.Namespace).Get(ref.Name)
 		if apierrors.IsNotFound(err) {
-			availableCondition.Status = apiregistration.ConditionFalse
-			availableCondition.Reason = "EndpointsNotFound"
-			availableCondition.Message = fmt.Sprintf("cannot find endpoints for service/%s in %q", apiService.Spec.Service.Name, apiService.Spec.Service.Namespace)
-			apiregistration.SetAPIServiceCondition(apiService, availableCondition)
-			_, err := c.apiServiceClient.APIServices().UpdateStatus(apiService)
-			return err
+			available.Status = apiregistration.ConditionFalse
+			if len(service.Spec.Selector) == 0 {
+				// the endpoints controller only populates Endpoints for a Service that has a
+				// selector; a selector-less Service missing its Endpoints is expected to be
+				// managed some other way, so "no endpoints yet" isn't the useful signal here.
+				available.Reason = string(apiregistration.AvailabilityReasonServiceHasNoSelector)
+				available.Message = fmt.Sprintf("service/%s in %q has no selector and no manually-managed endpoints", ref.Name, ref.Namespace)
+			} else {
+				available.Reason = string(apiregistration.AvailabilityReasonEndpointsNotFound)
+				available.Message = fmt.Sprintf("cannot find endpoints for service/%s in %q", ref.Name, ref.Namespace)
+			}
+			return available, degraded, false, nil
 		} else if err != nil {
-			availableCondition.Status = apiregistration.ConditionUnknown
-			availableCondition.Reason = "EndpointsAccessError"
-			availableCondition.Message = fmt.Sprintf("service/%s in %q cannot be checked due to: %v", apiService.Spec.Service.Name, apiService.Spec.Service.Namespace, err)
-			apiregistration.SetAPIServiceCondition(apiService, availableCondition)
-			_, err := c.apiServiceClient.APIServices().UpdateStatus(apiService)
-			return err
-		}
-		hasActiveEndpoints := false
+			available.Status = apiregistration.ConditionUnknown
+			available.Reason = string(apiregistration.AvailabilityReasonEndpointsAccessError)
+			available.Message = fmt.Sprintf("service/%s in %q cannot be checked due to: %v", ref.Name, ref.Namespace, err)
+			return available, degraded, false, nil
+		}
 		for _, subset := range endpoints.Subsets {
-			if len(subset.Addresses) > 0 {
-				hasActiveEndpoints = true
-				break
+			readyAddresses += len(subset.Addresses)
+			notReadyAddresses += len(subset.NotReadyAddresses)
+		}
+		// each of these checks is independent of the others -- unlike a Service lookup failing
+		// and pre-empting any Endpoints check, a port mismatch and an endpoint readiness problem
+		// can both be true of the same Endpoints at once. Evaluate all of them and combine
+		// whatever's wrong into one message, rather than reporting only whichever happens to be
+		// checked first and leaving the rest to be discovered one resync at a time.
+		var failures []AvailabilityFailure
+		if reason, message, ok := c.evaluateEndpointReadiness(readyAddresses, notReadyAddresses, ref); !ok {
+			if readyAddresses == 0 && c.PodTerminatingLookup != nil && c.allNotReadyAddressesTerminating(endpoints) {
+				reason = string(apiregistration.AvailabilityReasonEndpointsTerminating)
+				message = fmt.Sprintf("endpoints for service/%s in %q are all backed by terminating pods", ref.Name, ref.Namespace)
 			}
+			failures = append(failures, AvailabilityFailure{Reason: apiregistration.AvailabilityReason(reason), Message: message})
+		}
+		if readyAddresses > 0 && len(service.Spec.Ports) > 0 && !hasReadyPortMatch(endpoints, service.Spec.Ports) {
+			failures = append(failures, AvailabilityFailure{
+				Reason:  apiregistration.AvailabilityReasonNoReadyPorts,
+				Message: fmt.Sprintf("service/%s in %q has %d ready endpoint address(es), but none are served on any of the service's ports (%s)", ref.Name, ref.Namespace, readyAddresses, strings.Join(servicePortNumbers(service.Spec.Ports), ", ")),
+			})
+		}
+		if c.LocalZone != "" && c.NodeZoneLookup != nil && !c.hasLocalZoneReadyAddress(endpoints) {
+			failures = append(failures, AvailabilityFailure{
+				Reason:  apiregistration.AvailabilityReasonNoLocalZoneEndpoints,
+				Message: fmt.Sprintf("service/%s in %q has %d ready endpoint address(es), but none in zone %q", ref.Name, ref.Namespace, readyAddresses, c.LocalZone),
+			})
 		}
-		if !hasActiveEndpoints {
-			availableCondition.Status = apiregistration.ConditionFalse
-			availableCondition.Reason = "MissingEndpoints"
-			availableCondition.Message = fmt.Sprintf("endpoints for service/%s in %q have no addresses", apiService.Spec.Service.Name, apiService.Spec.Service.Namespace)
-			apiregistration.SetAPIServiceCondition(apiService, availableCondition)
-			_, err := c.apiServiceClient.APIServices().UpdateStatus(apiService)
-			return err
+		if len(failures) > 0 {
+			available.Status = apiregistration.ConditionFalse
+			available.Reason, available.Message = CombineAvailabilityFailures(failures)
+			return available, degraded, false, nil
 		}
 	}
-	// actually try to hit the discovery endpoint when it isn't local and when we're routing as a service.
-	if apiService.Spec.Service != nil && c.serviceResolver != nil {
-		discoveryURL, err := c.serviceResolver.ResolveEndpoint(apiService.Spec.Service.Namespace, apiService.Spec.Service.Name)
+	// actually try to hit the discovery endpoint when we're routing as a service.
+	probedPort := ""
+	if c.serviceResolver != nil {
+		discoveryURL, err := c.serviceResolver.ResolveEndpoint(ref.Namespace, ref.Name)
 		if err != nil {
-			return err
+			available.Status = apiregistration.ConditionFalse
+			available.Reason = string(apiregistration.AvailabilityReasonFailedDiscoveryCheck)
+			available.Message = fmt.Sprintf("service/%s in %q could not be resolved to a discovery endpoint on port %d: %v", ref.Name, ref.Namespace, apiServicePort, err)
+			// force a requeue, same as every other discovery failure below.
+			return available, degraded, false, err
 		}
-
-		errCh := make(chan error)
-		go func() {
-			resp, err := c.discoveryClient.Get(discoveryURL.String())
-			if resp != nil {
-				resp.Body.Close()
-			}
-			errCh <- err
-		}()
-
-		select {
-		case err = <-errCh:
-
-		// we had trouble with slow dial and DNS responses causing us to wait too long.
-		// we added this as insurance
-		case <-time.After(6 * time.Second):
-			err = fmt.Errorf("timed out waiting for %v", discoveryURL)
+		if _, err := probeURL(discoveryURL, apiService); err != nil {
+			available.Status = apiregistration.ConditionFalse
+			available.Reason = string(apiregistration.AvailabilityReasonFailedDiscoveryCheck)
+			available.Message = fmt.Sprintf("service/%s in %q resolved to an unusable discovery endpoint: %v", ref.Name, ref.Namespace, err)
+			// force a requeue, same as every other discovery failure below.
+			return available, degraded, false, err
 		}
+		probedPort = discoveryURL.Port()
 
+		tlsConfig, err := tlsConfigForAPIService(apiService.Spec)
 		if err != nil {
-			availableCondition.Status = apiregistration.ConditionFalse
-			availableCondition.Reason = "FailedDiscoveryCheck"
-			availableCondition.Message = fmt.Sprintf("no response from %v: %v", discoveryURL, err)
-			apiregistration.SetAPIServiceCondition(apiService, availableCondition)
-			_, updateErr := c.apiServiceClient.APIServices().UpdateStatus(apiService)
-			if updateErr != nil {
-				return updateErr
+			available.Status = apiregistration.ConditionFalse
+			available.Reason = string(apiregistration.AvailabilityReasonInvalidCABundle)
+			available.Message = fmt.Sprintf("the CABundle for service/%s in %q could not be parsed into a certificate pool: %v", ref.Name, ref.Namespace, err)
+			// force a requeue, same as every other discovery failure below.
+			return available, degraded, false, err
+		}
+
+		probeHost := apiService.Annotations[apiregistration.ProbeHostOverrideAnnotation]
+		expectedGroupVersion := ""
+		if c.ValidateAdvertisedGroupVersion {
+			expectedGroupVersion = apiService.Spec.Group + "/" + apiService.Spec.Version
+		}
+		if err := c.probe(ctx, discoveryURL, tlsConfig, probeHost, expectedGroupVersion); err != nil {
+			_, unauthorized := err.(*ErrProbeUnauthorized)
+			if !unauthorized || !c.TreatUnauthorizedAsAvailable {
+				available.Status = apiregistration.ConditionFalse
+				if circuitOpen, ok := err.(*ErrCircuitBreakerOpen); ok {
+					available.Reason = string(apiregistration.AvailabilityReasonCircuitBreakerOpen)
+					available.Message = fmt.Sprintf("circuit breaker for %s is open after repeated consecutive failures; last failure: %v", circuitOpen.Host, circuitOpen.LastErr)
+				} else if redirect, ok := err.(*ErrProbeRedirect); ok {
+					available.Reason = string(apiregistration.AvailabilityReasonDiscoveryUnexpectedRedirect)
+					available.Message = fmt.Sprintf("discovery endpoint %v returned %d redirect to %q", discoveryURL, redirect.StatusCode, redirect.Location)
+				} else if notAdvertised, ok := err.(*ErrGroupVersionNotAdvertised); ok {
+					available.Reason = string(apiregistration.AvailabilityReasonGroupVersionNotAdvertised)
+					available.Message = fmt.Sprintf("discovery response from %v did not advertise group/version %q: %v", discoveryURL, notAdvertised.Expected, notAdvertised)
+				} else if badContentType, ok := err.(*ErrProbeBadContentType); ok {
+					available.Reason = string(apiregistration.AvailabilityReasonDiscoveryBadContentType)
+					available.Message = fmt.Sprintf("discovery response from %v had unexpected content-type: %v", discoveryURL, badContentType)
+				} else if http2Err, ok := rootHTTP2Error(err); ok {
+					available.Reason = string(apiregistration.AvailabilityReasonDiscoveryProtocolError)
+					available.Message = fmt.Sprintf("HTTP/2 error talking to %v: %v", discoveryURL, http2Err)
+				} else if dnsErr, ok := rootDNSError(err); ok {
+					available.Reason = string(apiregistration.AvailabilityReasonServiceDNSResolutionFailed)
+					available.Message = fmt.Sprintf("failed to resolve %q: %v", dnsErr.Name, dnsErr)
+				} else if connRefusedErr, ok := rootConnectionRefusedError(err); ok {
+					available.Reason = string(apiregistration.AvailabilityReasonServiceConnectionRefused)
+					available.Message = fmt.Sprintf("connection to %v was refused: %v", discoveryURL, connRefusedErr)
+				} else if isProbeTimeout(err) {
+					available.Reason = string(apiregistration.AvailabilityReasonDiscoveryTimeout)
+					available.Message = fmt.Sprintf("timed out waiting for a response from %v: %v", discoveryURL, err)
+				} else if probeFailed, ok := err.(*ErrProbeFailed); ok && probeFailed.StatusCode == http.StatusNotFound {
+					available.Reason = string(apiregistration.AvailabilityReasonDiscoveryNotFound)
+					available.Message = fmt.Sprintf("discovery endpoint %v returned 404, the discovery path may be misconfigured", discoveryURL)
+				} else if probeFailed, ok := err.(*ErrProbeFailed); ok && probeFailed.StatusCode >= http.StatusInternalServerError {
+					available.Reason = string(apiregistration.AvailabilityReasonDiscoveryServerError)
+					available.Message = fmt.Sprintf("discovery endpoint %v returned %d: %v", discoveryURL, probeFailed.StatusCode, err)
+				} else {
+					available.Reason = string(apiregistration.AvailabilityReasonFailedDiscoveryCheck)
+					available.Message = fmt.Sprintf("no response from %v: %v", discoveryURL, err)
+				}
+				// force a requeue to make it very obvious that this will be retried at some point
+				// in the future along with other requeues done via service change, endpoint
+				// change, and resync.
+				return available, degraded, false, err
 			}
-			// force a requeue to make it very obvious that this will be retried at some point in the future
-			// along with other requeues done via service change, endpoint change, and resync
-			return err
+			// the backend is reachable but rejected our probe credentials; treat it as
+			// available per TreatUnauthorizedAsAvailable and fall through to "Passed".
 		}
 	}
 
-	availableCondition.Reason = "Passed"
-	availableCondition.Message = "all checks passed"
-	apiregistration.SetAPIServiceCondition(apiService, availableCondition)
-	_, err = c.apiServiceClient.APIServices().UpdateStatus(apiService)
-	return err
+	available.Reason = string(apiregistration.AvailabilityReasonPassed)
+	if probedPort != "" {
+		available.Message = fmt.Sprintf("all checks passed (service type %s, %d ready endpoint address(es), probed port %s)", service.Spec.Type, readyAddresses, probedPort)
+	} else {
+		available.Message = fmt.Sprintf("all checks passed (service type %s, %d ready endpoint address(es))", service.Spec.Type, readyAddresses)
+	}
+
+	degraded = apiregistration.APIServiceCondition{
+		Type:               apiregistration.Degraded,
+		LastTransitionTime: metav1.NewTime(c.clockOrDefault().Now()),
+	}
+	if notReadyAddresses > 0 {
+		degraded.Status = apiregistration.ConditionTrue
+		degraded.Reason = string(apiregistration.DegradedReasonEndpointsPartiallyReady)
+		degraded.Message = fmt.Sprintf("service/%s in %q has %d not-ready endpoint address(es) alongside %d ready", ref.Name, ref.Namespace, notReadyAddresses, readyAddresses)
+	} else {
+		degraded.Status = apiregistration.ConditionFalse
+		degraded.Reason = string(apiregistration.DegradedReasonEndpointsFullyReady)
+		degraded.Message = fmt.Sprintf("all %d known endpoint address(es) are ready", readyAddresses)
+	}
+	return available, degraded, true, nil
+}
+
+// SetNamespaceLister configures an optional NamespaceLister, used to add more detail to the
+// ServiceNotFound reason when the backing Service's namespace is being deleted.
+func (c *AvailableConditionController) SetNamespaceLister(namespaceLister v1listers.NamespaceLister) {
+	c.namespaceLister = namespaceLister
+}
+
+// SetLocalDelegateHealthChecker configures an optional readiness hook for local APIServices
+// (those with no Spec.Service). When set, it's called before declaring a local APIService
+// available; a non-nil error marks it unavailable with reason LocalHealthCheckFailed instead of
+// the usual always-true Local reason. Leaving this unset preserves the previous behavior of
+// treating every local APIService as unconditionally available.
+func (c *AvailableConditionController) SetLocalDelegateHealthChecker(check func() error) {
+	c.localDelegateHealthChecker = check
+}
+
+// SetProbeUserAgent overrides the User-Agent header sent with discovery probes. It only has an
+// effect when the controller is still using the default, HTTP-backed Prober; it's a no-op if a
+// custom Prober was injected (e.g. in tests).
+func (c *AvailableConditionController) SetProbeUserAgent(userAgent string) {
+	if p, ok := c.prober.(*clientProber); ok {
+		p.UserAgent = userAgent
+	}
+}
+
+// SetRequiredDiscoveryContentType makes the discovery probe fail with reason
+// DiscoveryBadContentType unless the response's Content-Type (ignoring parameters) matches
+// contentType, instead of accepting any 2xx response regardless of body. It only has an effect
+// when the controller is still using the default, HTTP-backed Prober; it's a no-op if a custom
+// Prober was injected (e.g. in tests).
+func (c *AvailableConditionController) SetRequiredDiscoveryContentType(contentType string) {
+	if p, ok := c.prober.(*clientProber); ok {
+		p.RequiredContentType = contentType
+	}
+}
+
+// SetAcceptableProbeStatusCodes replaces the default 200-299 range as the set of discovery probe
+// response status codes treated as success, for backends that legitimately respond with
+// something like 204 or 304. It only has an effect when the controller is still using the
+// default, HTTP-backed Prober; it's a no-op if a custom Prober was injected (e.g. in tests).
+func (c *AvailableConditionController) SetAcceptableProbeStatusCodes(statusCodes []int) {
+	if p, ok := c.prober.(*clientProber); ok {
+		p.AcceptableStatusCodes = statusCodes
+	}
+}
+
+// SetProbeHeaders sets a static header set included verbatim on every discovery probe request,
+// for backends that require an identity or routing header (e.g. a tenant id) to serve discovery.
+// It only has an effect when the controller is still using the default, HTTP-backed Prober; it's
+// a no-op if a custom Prober was injected (e.g. in tests).
+func (c *AvailableConditionController) SetProbeHeaders(headers http.Header) {
+	if p, ok := c.prober.(*clientProber); ok {
+		p.Headers = headers
+	}
+}
+
+// SetProbeProxyURL makes the discovery probe route through proxyURL instead of consulting
+// HTTP_PROXY/HTTPS_PROXY/NO_PROXY, for deployments where the aggregator can only reach backends
+// through a specific egress proxy. It only has an effect when the controller is still using the
+// default, HTTP-backed Prober; it's a no-op if a custom Prober was injected (e.g. in tests).
+func (c *AvailableConditionController) SetProbeProxyURL(proxyURL *url.URL) {
+	if p, ok := c.prober.(*clientProber); ok {
+		p.Proxy = http.ProxyURL(proxyURL)
+	}
+}
+
+// SetProbeTransportLimits configures MaxIdleConnsPerHost and IdleConnTimeout on the discovery
+// probe transport, so a large cluster with many backends doesn't exhaust file descriptors
+// keeping idle probe connections open. It only has an effect when the controller is still using
+// the default, HTTP-backed Prober; it's a no-op if a custom Prober was injected (e.g. in tests).
+func (c *AvailableConditionController) SetProbeTransportLimits(maxIdleConnsPerHost int, idleConnTimeout time.Duration) {
+	p, ok := c.prober.(*clientProber)
+	if !ok {
+		return
+	}
+	p.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	p.IdleConnTimeout = idleConnTimeout
+	if baseTransport, ok := p.discoveryClient.Transport.(*http.Transport); ok {
+		baseTransport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+		baseTransport.IdleConnTimeout = idleConnTimeout
+	}
+}
+
+// SetProbeClientCertificate loads an X.509 key pair from certFile/keyFile and presents it during
+// the discovery probe's TLS handshake, for backends configured to require mutual TLS. It only has
+// an effect when the controller is still using the default, HTTP-backed Prober; it's a no-op if a
+// custom Prober was injected (e.g. in tests).
+func (c *AvailableConditionController) SetProbeClientCertificate(certFile, keyFile string) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load probe client certificate: %v", err)
+	}
+	if p, ok := c.prober.(*clientProber); ok {
+		p.ClientCertificates = []tls.Certificate{cert}
+	}
+	return nil
+}
+
+// WaitForCacheSync blocks until the APIService, Service, and Endpoints informers backing this
+// controller have completed their initial list, or stopCh is closed. Callers that want to do
+// their own readiness gating (e.g. before marking a server healthy) can call this directly
+// instead of going through Run.
+func (c *AvailableConditionController) WaitForCacheSync(stopCh <-chan struct{}) bool {
+	return controllers.WaitForCacheSync("AvailableConditionController", stopCh, c.apiServiceSynced, c.servicesSynced, c.endpointsSynced)
 }
 
 func (c *AvailableConditionController) Run(threadiness int, stopCh <-chan struct{}) {
@@ -262,7 +1750,7 @@ func (c *AvailableConditionController) Run(threadiness int, stopCh <-chan struct
 	glog.Infof("Starting AvailableConditionController")
 	defer glog.Infof("Shutting down AvailableConditionController")
 
-	if !controllers.WaitForCacheSync("AvailableConditionController", stopCh, c.apiServiceSynced, c.servicesSynced, c.endpointsSynced) {
+	if !c.WaitForCacheSync(stopCh) {
 		return
 	}
 
@@ -278,8 +1766,36 @@ func (c *AvailableConditionController) runWorker() {
 	}
 }
 
+// Pause makes workers block instead of processing queued keys, until Resume is called. Keys
+// already queued, and any enqueued while paused, are preserved and processed once resumed.
+func (c *AvailableConditionController) Pause() {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+	c.paused = true
+}
+
+// Resume undoes a prior Pause, waking workers blocked waiting out the pause so they resume
+// processing queued keys.
+func (c *AvailableConditionController) Resume() {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+	c.paused = false
+	c.pauseCond.Broadcast()
+}
+
+// waitWhilePaused blocks the calling worker goroutine for as long as the controller is paused.
+func (c *AvailableConditionController) waitWhilePaused() {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+	for c.paused {
+		c.pauseCond.Wait()
+	}
+}
+
 // processNextWorkItem deals with one key off the queue.  It returns false when it's time to quit.
 func (c *AvailableConditionController) processNextWorkItem() bool {
+	c.waitWhilePaused()
+
 	key, quit := c.queue.Get()
 	if quit {
 		return false
@@ -335,6 +1851,8 @@ func (c *AvailableConditionController) deleteAPIService(obj interface{}) {
 		}
 	}
 	glog.V(4).Infof("Deleting %q", castObj.Name)
+	c.syncingAPIServices.Delete(castObj.Name)
+	deleteAvailabilityMetrics(castObj.Name)
 	c.enqueue(castObj)
 }
 
@@ -349,17 +1867,27 @@ func (c *AvailableConditionController) getAPIServicesFor(obj runtime.Object) []*
 	var ret []*apiregistration.APIService
 	apiServiceList, _ := c.apiServiceLister.List(labels.Everything())
 	for _, apiService := range apiServiceList {
-		if apiService.Spec.Service == nil {
+		if refersToService(apiService.Spec.Service, metadata) {
+			ret = append(ret, apiService)
 			continue
 		}
-		if apiService.Spec.Service.Namespace == metadata.GetNamespace() && apiService.Spec.Service.Name == metadata.GetName() {
-			ret = append(ret, apiService)
+		for i := range apiService.Spec.FallbackServices {
+			if refersToService(&apiService.Spec.FallbackServices[i], metadata) {
+				ret = append(ret, apiService)
+				break
+			}
 		}
 	}
 
 	return ret
 }
 
+// refersToService reports whether ref names the Service or Endpoints object described by metadata.
+// A nil ref (an APIService with no primary Spec.Service, e.g. a local one) never matches.
+func refersToService(ref *apiregistration.ServiceReference, metadata metav1.Object) bool {
+	return ref != nil && ref.Namespace == metadata.GetNamespace() && ref.Name == metadata.GetName()
+}
+
 // TODO, think of a way to avoid checking on every service manipulation
 
 func (c *AvailableConditionController) addService(obj interface{}) {
@@ -17,18 +17,80 @@ limitations under the License.
 package apiserver
 
 import (
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
 
 	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/clock"
 	v1listers "k8s.io/client-go/listers/core/v1"
 	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/kube-aggregator/pkg/apis/apiregistration"
 	"k8s.io/kube-aggregator/pkg/client/clientset_generated/internalclientset/fake"
 	listers "k8s.io/kube-aggregator/pkg/client/listers/apiregistration/internalversion"
 )
 
+// fakeServiceResolver always resolves to the same URL, regardless of namespace/name.
+type fakeServiceResolver struct {
+	url *url.URL
+}
+
+func (f *fakeServiceResolver) ResolveEndpoint(namespace, name string) (*url.URL, error) {
+	return f.url, nil
+}
+
+// failingServiceResolver always fails to resolve, simulating a backing Service with no usable
+// endpoint (e.g. no endpoints at all, or none exposing the expected port).
+type failingServiceResolver struct {
+	err error
+}
+
+func (f *failingServiceResolver) ResolveEndpoint(namespace, name string) (*url.URL, error) {
+	return nil, f.err
+}
+
+// fakeProber returns err for every Probe call, letting tests exercise both the
+// success and failure paths without a real HTTP stack.
+type fakeProber struct {
+	err        error
+	called     bool
+	probedHost string
+}
+
+func (f *fakeProber) Probe(discoveryURL *url.URL, tlsConfig *tls.Config, probeHost, expectedGroupVersion string) error {
+	f.called = true
+	f.probedHost = probeHost
+	return f.err
+}
+
 func newEndpoints(namespace, name string) *v1.Endpoints {
 	return &v1.Endpoints{
 		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
@@ -50,11 +112,49 @@ func newEndpointsWithAddress(namespace, name string) *v1.Endpoints {
 	}
 }
 
+// newEndpointsWithReadyAndNotReady returns an Endpoints object with a single subset containing
+// the given number of ready and not-ready addresses, for exercising EndpointReadinessPolicy.
+func newEndpointsWithReadyAndNotReady(namespace, name string, ready, notReady int) *v1.Endpoints {
+	subset := v1.EndpointSubset{}
+	for i := 0; i < ready; i++ {
+		subset.Addresses = append(subset.Addresses, v1.EndpointAddress{IP: fmt.Sprintf("10.0.0.%d", i)})
+	}
+	for i := 0; i < notReady; i++ {
+		subset.NotReadyAddresses = append(subset.NotReadyAddresses, v1.EndpointAddress{IP: fmt.Sprintf("10.0.1.%d", i)})
+	}
+	return &v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Subsets:    []v1.EndpointSubset{subset},
+	}
+}
+
 func newService(namespace, name string) *v1.Service {
 	return &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
 		Spec: v1.ServiceSpec{
-			Type: v1.ServiceTypeClusterIP,
+			Type:     v1.ServiceTypeClusterIP,
+			Selector: map[string]string{"app": name},
+		},
+	}
+}
+
+// newServiceWithoutSelector returns a ClusterIP Service with no selector, which the endpoints
+// controller will never populate Endpoints for.
+func newServiceWithoutSelector(namespace, name string) *v1.Service {
+	service := newService(namespace, name)
+	service.Spec.Selector = nil
+	return service
+}
+
+// newHeadlessService returns a headless (ClusterIP: None) ClusterIP-type Service, which is
+// routed the same way as a normal ClusterIP service for availability purposes: by looking at
+// its Endpoints rather than a cluster-assigned virtual IP.
+func newHeadlessService(namespace, name string) *v1.Service {
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: v1.ServiceSpec{
+			Type:      v1.ServiceTypeClusterIP,
+			ClusterIP: v1.ClusterIPNone,
 		},
 	}
 }
@@ -69,6 +169,8 @@ func newRemoteAPIService(name string) *apiregistration.APIService {
 	return &apiregistration.APIService{
 		ObjectMeta: metav1.ObjectMeta{Name: name},
 		Spec: apiregistration.APIServiceSpec{
+			Group:   "group",
+			Version: "v1",
 			Service: &apiregistration.ServiceReference{
 				Namespace: "foo",
 				Name:      "bar",
@@ -122,6 +224,18 @@ func TestSync(t *testing.T) {
 				Message: `cannot find endpoints for service/bar in "foo"`,
 			},
 		},
+		{
+			name:           "service has no selector and no endpoints",
+			apiServiceName: "remote.group",
+			apiServices:    []*apiregistration.APIService{newRemoteAPIService("remote.group")},
+			services:       []*v1.Service{newServiceWithoutSelector("foo", "bar")},
+			expectedAvailability: apiregistration.APIServiceCondition{
+				Type:    apiregistration.Available,
+				Status:  apiregistration.ConditionFalse,
+				Reason:  "ServiceHasNoSelector",
+				Message: `service/bar in "foo" has no selector and no manually-managed endpoints`,
+			},
+		},
 		{
 			name:           "missing endpoints",
 			apiServiceName: "remote.group",
@@ -145,7 +259,33 @@ func TestSync(t *testing.T) {
 				Type:    apiregistration.Available,
 				Status:  apiregistration.ConditionTrue,
 				Reason:  "Passed",
-				Message: `all checks passed`,
+				Message: `all checks passed (service type ClusterIP, 1 ready endpoint address(es))`,
+			},
+		},
+		{
+			name:           "headless with endpoints",
+			apiServiceName: "remote.group",
+			apiServices:    []*apiregistration.APIService{newRemoteAPIService("remote.group")},
+			services:       []*v1.Service{newHeadlessService("foo", "bar")},
+			endpoints:      []*v1.Endpoints{newEndpointsWithAddress("foo", "bar")},
+			expectedAvailability: apiregistration.APIServiceCondition{
+				Type:    apiregistration.Available,
+				Status:  apiregistration.ConditionTrue,
+				Reason:  "Passed",
+				Message: `all checks passed (service type ClusterIP, 1 ready endpoint address(es))`,
+			},
+		},
+		{
+			name:           "headless without endpoints",
+			apiServiceName: "remote.group",
+			apiServices:    []*apiregistration.APIService{newRemoteAPIService("remote.group")},
+			services:       []*v1.Service{newHeadlessService("foo", "bar")},
+			endpoints:      []*v1.Endpoints{newEndpoints("foo", "bar")},
+			expectedAvailability: apiregistration.APIServiceCondition{
+				Type:    apiregistration.Available,
+				Status:  apiregistration.ConditionFalse,
+				Reason:  "MissingEndpoints",
+				Message: `endpoints for service/bar in "foo" have no addresses`,
 			},
 		},
 	}
@@ -185,22 +325,2450 @@ func TestSync(t *testing.T) {
 			continue
 		}
 
-		if e, a := 1, len(action.GetObject().(*apiregistration.APIService).Status.Conditions); e != a {
-			t.Errorf("%v expected %v, got %v", tc.name, e, action.GetObject())
+		// a successful probe also writes a Degraded condition alongside Available (see
+		// updateAvailableAndDegradedConditions), so look up Available by type instead of assuming
+		// it's the only condition present.
+		condition := apiregistration.GetAPIServiceConditionByType(action.GetObject().(*apiregistration.APIService), apiregistration.Available)
+		if condition == nil {
+			t.Errorf("%v expected an Available condition, got %#v", tc.name, action.GetObject())
 			continue
 		}
-		condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
-		if e, a := tc.expectedAvailability.Type, condition.Type; e != a {
-			t.Errorf("%v expected %v, got %#v", tc.name, e, condition)
+		if !tc.expectedAvailability.Equal(*condition) {
+			t.Errorf("%v expected %#v, got %#v", tc.name, tc.expectedAvailability, *condition)
+		}
+	}
+}
+
+// TestUnavailableAPIServices checks that UnavailableAPIServices returns only the APIServices
+// whose Available condition is not strictly True, including ones with no condition at all.
+func TestUnavailableAPIServices(t *testing.T) {
+	available := newLocalAPIService("v1.")
+	apiregistration.SetAPIServiceCondition(available, apiregistration.APIServiceCondition{
+		Type:   apiregistration.Available,
+		Status: apiregistration.ConditionTrue,
+	})
+	unavailable := newRemoteAPIService("remote.group")
+	apiregistration.SetAPIServiceCondition(unavailable, apiregistration.APIServiceCondition{
+		Type:   apiregistration.Available,
+		Status: apiregistration.ConditionFalse,
+	})
+	unsynced := newRemoteAPIService("unsynced.group")
+
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(available)
+	apiServiceIndexer.Add(unavailable)
+	apiServiceIndexer.Add(unsynced)
+
+	c := AvailableConditionController{
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+	}
+	got, err := c.UnavailableAPIServices()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 unavailable APIServices, got %d: %v", len(got), got)
+	}
+	names := map[string]bool{got[0].Name: true, got[1].Name: true}
+	if !names["remote.group"] || !names["unsynced.group"] {
+		t.Errorf("expected remote.group and unsynced.group, got %v", names)
+	}
+}
+
+// TestStaleAPIServices uses a fake clock advanced past the configured max age to verify that
+// StaleAPIServices flags an APIService whose Available condition hasn't been refreshed
+// recently, while leaving a freshly-synced one alone.
+func TestStaleAPIServices(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	fresh := newLocalAPIService("v1.")
+	apiregistration.SetAPIServiceCondition(fresh, apiregistration.APIServiceCondition{
+		Type:               apiregistration.Available,
+		Status:             apiregistration.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(fakeClock.Now()),
+	})
+
+	stale := newRemoteAPIService("remote.group")
+	apiregistration.SetAPIServiceCondition(stale, apiregistration.APIServiceCondition{
+		Type:               apiregistration.Available,
+		Status:             apiregistration.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(fakeClock.Now()),
+	})
+
+	unsynced := newRemoteAPIService("unsynced.group")
+
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(fresh)
+	apiServiceIndexer.Add(stale)
+	apiServiceIndexer.Add(unsynced)
+
+	c := AvailableConditionController{
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+	}
+	c.SetClock(fakeClock)
+
+	// advance past the max age for "stale" and "unsynced", but refresh "fresh" right before
+	// checking so it stays within the threshold. SetAPIServiceCondition only bumps
+	// LastTransitionTime when Status itself changes, so re-applying the same ConditionTrue
+	// status wouldn't actually refresh it here -- update the existing condition's
+	// LastTransitionTime directly instead.
+	fakeClock.Step(2 * time.Hour)
+	freshCondition := apiregistration.GetAPIServiceConditionByType(fresh, apiregistration.Available)
+	freshCondition.LastTransitionTime = metav1.NewTime(fakeClock.Now())
+
+	got, err := c.StaleAPIServices(time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 stale APIServices, got %d: %v", len(got), got)
+	}
+	names := map[string]bool{got[0].Name: true, got[1].Name: true}
+	if !names["remote.group"] || !names["unsynced.group"] {
+		t.Errorf("expected remote.group and unsynced.group to be stale, got %v", names)
+	}
+}
+
+// TestSnapshotJSON seeds a few APIServices with different Available conditions (including none
+// at all) and checks that SnapshotJSON renders each one as the documented JSON shape.
+func TestSnapshotJSON(t *testing.T) {
+	transitionTime := metav1.NewTime(time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	available := newLocalAPIService("v1.")
+	apiregistration.SetAPIServiceCondition(available, apiregistration.APIServiceCondition{
+		Type:               apiregistration.Available,
+		Status:             apiregistration.ConditionTrue,
+		Reason:             "Passed",
+		Message:            "all checks passed",
+		LastTransitionTime: transitionTime,
+	})
+
+	unavailable := newRemoteAPIService("remote.group")
+	apiregistration.SetAPIServiceCondition(unavailable, apiregistration.APIServiceCondition{
+		Type:               apiregistration.Available,
+		Status:             apiregistration.ConditionFalse,
+		Reason:             "FailedDiscoveryCheck",
+		Message:            "no response",
+		LastTransitionTime: transitionTime,
+	})
+
+	unsynced := newRemoteAPIService("unsynced.group")
+
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(available)
+	apiServiceIndexer.Add(unavailable)
+	apiServiceIndexer.Add(unsynced)
+
+	c := AvailableConditionController{
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+	}
+
+	data, err := c.SnapshotJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var snapshot []APIServiceAvailabilitySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("failed to unmarshal snapshot: %v", err)
+	}
+
+	want := []APIServiceAvailabilitySnapshot{
+		{Name: "remote.group", Available: false, Reason: "FailedDiscoveryCheck", Message: "no response", LastTransitionTime: transitionTime},
+		{Name: "unsynced.group"},
+		{Name: "v1.", Available: true, Reason: "Passed", Message: "all checks passed", LastTransitionTime: transitionTime},
+	}
+	if len(snapshot) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %#v", len(want), len(snapshot), snapshot)
+	}
+	for i := range want {
+		got, expected := snapshot[i], want[i]
+		if got.Name != expected.Name || got.Available != expected.Available || got.Reason != expected.Reason || got.Message != expected.Message || !got.LastTransitionTime.Equal(&expected.LastTransitionTime) {
+			t.Errorf("entry %d: expected %#v, got %#v", i, expected, got)
+		}
+	}
+}
+
+func TestTLSConfigForAPIService(t *testing.T) {
+	insecure, err := tlsConfigForAPIService(apiregistration.APIServiceSpec{InsecureSkipTLSVerify: true})
+	if err != nil || !insecure.InsecureSkipVerify {
+		t.Errorf("expected InsecureSkipTLSVerify to produce an InsecureSkipVerify config, got %+v (err=%v)", insecure, err)
+	}
+
+	noBundle, err := tlsConfigForAPIService(apiregistration.APIServiceSpec{})
+	if err != nil || !noBundle.InsecureSkipVerify {
+		t.Errorf("expected no CABundle to fall back to InsecureSkipVerify, got %+v (err=%v)", noBundle, err)
+	}
+
+	if _, err := tlsConfigForAPIService(apiregistration.APIServiceSpec{CABundle: []byte("not a cert")}); err == nil {
+		t.Errorf("expected an unparseable CABundle to be rejected")
+	}
+
+	validated, err := tlsConfigForAPIService(apiregistration.APIServiceSpec{CABundle: []byte(testCACert)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if validated.InsecureSkipVerify || validated.RootCAs == nil {
+		t.Errorf("expected a valid CABundle to produce a verifying config with RootCAs set, got %+v", validated)
+	}
+}
+
+// testCACert is an arbitrary, self-signed PEM certificate used only to exercise the
+// CABundle-parsing path above; it is never used to dial anything.
+const testCACert = `-----BEGIN CERTIFICATE-----
+MIIC/zCCAeegAwIBAgIUVxcTtQ6z6KZEUua0PJjLFfAfwTYwDQYJKoZIhvcNAQEL
+BQAwDzENMAsGA1UEAwwEdGVzdDAeFw0yNjA4MDkxMjU2MzdaFw0zNjA4MDYxMjU2
+MzdaMA8xDTALBgNVBAMMBHRlc3QwggEiMA0GCSqGSIb3DQEBAQUAA4IBDwAwggEK
+AoIBAQCzaRpUhiirFcRXuqJM5dc0JOINUfnkQBrr4l5RuG+00xGcO1I10UaXzorM
+N0i3yttJZpCCjkcum8/Jul+q+Vh5AHhyCGidGkaJBrAGvNa0D7wf48He3qaFdey0
+IRylP1r0UfFSYfI8wVJ+yWKOfYA5EFneZyI+XTzKER+MDsgiYsSP9PG8K5LzvYQa
+MfRfBoGmYTGPJ0sNAGRX8H6Szflcln/Jvte+Eg6zg+CQoOUn/9Rg40NUWRXxY9zh
+1k9YHke9XrQkA7QmZGrma3Moers+lP6evKX5Pj/umXmEBINu6SY9+xwpeLgmvEjC
+/OyTLCnrnEqOy5zjkUM/x0mIfSXVAgMBAAGjUzBRMB0GA1UdDgQWBBQl86w4Y6UK
+aYe0M832c6YJ5e1PMjAfBgNVHSMEGDAWgBQl86w4Y6UKaYe0M832c6YJ5e1PMjAP
+BgNVHRMBAf8EBTADAQH/MA0GCSqGSIb3DQEBCwUAA4IBAQCn2lE0eqJB4xaIzBOw
+NjUe4IkZLi2W+UOjO9cfy2mqAW7sxnz1HSJ7dZ8Yu6nk/7I3QwP6pE/YpcWRIHda
+xkaIuWkmwmzDn870tZJXWU81U9/+W6qzqbkDD4UQqU6O5ofhTmvyTzcLqw6HMlDf
+0wdOdu+tkYBx9S50Ttyq9vbxAdVPjD58IkxbjtWXirpWGbOfvuYob+N1zjqk2eGQ
+3oNiHOOcl8VHt7gpiBnjl0FvxUW8BmBRApXXI34RLIklSrShaRhq6Pt1TlBGhekl
+C0fxWfPBx1ILL1su+QJAR1yFtFjWFxMEe+NzfZ4SlUgJC5N8vQP+HoxD04uXSkKs
+0N3K
+-----END CERTIFICATE-----`
+
+// TestSyncSkipsCheckViaAnnotation verifies that an APIService with the disable-availability-check
+// annotation is reported available without the controller ever looking at its backing Service.
+func TestSyncSkipsCheckViaAnnotation(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	apiService.Annotations = map[string]string{apiregistration.DisableAvailabilityCheckAnnotation: "true"}
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+	}
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Status != apiregistration.ConditionTrue {
+		t.Errorf("expected the annotation to force Status=True, got %v", condition.Status)
+	}
+	if condition.Reason != "AvailabilityCheckSkipped" {
+		t.Errorf("expected reason AvailabilityCheckSkipped, got %v", condition.Reason)
+	}
+}
+
+// TestSyncUsesConfiguredClock checks that LastTransitionTime is stamped from the controller's
+// clock rather than the real wall clock, so tests (and anyone else who cares about exact
+// timestamps) can control it.
+func TestSyncUsesConfiguredClock(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	apiService.Spec.Service = nil
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+
+	fakeClock := clock.NewFakeClock(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+	}
+	c.SetClock(fakeClock)
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if !condition.LastTransitionTime.Time.Equal(fakeClock.Now()) {
+		t.Errorf("expected LastTransitionTime %v, got %v", fakeClock.Now(), condition.LastTransitionTime.Time)
+	}
+}
+
+// TestSyncNotifiesTransitionChannel checks that sync delivers an APIServiceTransition when an
+// APIService's Available condition changes status, and that the send doesn't block when no one
+// configured a channel at all.
+func TestSyncNotifiesTransitionChannel(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	apiService.Spec.Service = nil
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+
+	transitions := make(chan APIServiceTransition, 1)
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+	}
+	c.SetTransitionChannel(transitions)
+
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case transition := <-transitions:
+		if transition.Name != "remote.group" {
+			t.Errorf("expected Name %q, got %q", "remote.group", transition.Name)
+		}
+		if transition.OldStatus != apiregistration.ConditionUnknown {
+			t.Errorf("expected OldStatus %v for a brand new APIService, got %v", apiregistration.ConditionUnknown, transition.OldStatus)
+		}
+		if transition.NewStatus != apiregistration.ConditionTrue {
+			t.Errorf("expected NewStatus %v, got %v", apiregistration.ConditionTrue, transition.NewStatus)
+		}
+	default:
+		t.Fatalf("expected a transition event to be delivered")
+	}
+
+	// a second sync with no actual status change must not deliver another transition. Feed the
+	// lister the APIService as updated by the first sync, the way a real informer would see it
+	// come back around.
+	updated := fakeClient.Actions()[0].(clienttesting.UpdateAction).GetObject().(*apiregistration.APIService)
+	apiServiceIndexer.Update(updated)
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case transition := <-transitions:
+		t.Fatalf("expected no further transition event, got %+v", transition)
+	default:
+	}
+}
+
+// TestSyncRetriesOnStatusUpdateConflict checks that a Conflict error from UpdateStatus -- as
+// happens when another writer updates the same APIService between sync's read and its write --
+// doesn't fail the sync. Instead sync should refetch the latest version and retry, the way
+// updateAvailableCondition's retry.RetryOnConflict wrapping is meant to.
+func TestSyncRetriesOnStatusUpdateConflict(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	apiService.Spec.Service = nil
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+
+	// simulate another writer racing in an unrelated label change between sync's read and its
+	// UpdateStatus: the first UpdateStatus call fails with Conflict, and the object sync refetches
+	// on retry carries that label.
+	conflicted := false
+	fakeClient.PrependReactor("update", "apiservices", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if conflicted {
+			return false, nil, nil
+		}
+		conflicted = true
+		return true, nil, apierrors.NewConflict(apiregistration.Resource("apiservices"), apiService.Name, fmt.Errorf("the APIService has been modified; please apply your changes to the latest version and try again"))
+	})
+	fakeClient.PrependReactor("get", "apiservices", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		latest := apiService.DeepCopy()
+		latest.Labels = map[string]string{"raced-in": "true"}
+		return true, latest, nil
+	})
+
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+	}
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("expected the conflict to be retried away, got error: %v", err)
+	}
+
+	var updateActions []clienttesting.UpdateAction
+	for _, action := range fakeClient.Actions() {
+		if update, ok := action.(clienttesting.UpdateAction); ok {
+			updateActions = append(updateActions, update)
+		}
+	}
+	if e, a := 2, len(updateActions); e != a {
+		t.Fatalf("expected %d UpdateStatus attempts (one conflicting, one retried), got %d", e, a)
+	}
+	retried := updateActions[1].GetObject().(*apiregistration.APIService)
+	if retried.Labels["raced-in"] != "true" {
+		t.Errorf("expected the retry to be built on top of the refetched latest version, got labels %v", retried.Labels)
+	}
+	condition := apiregistration.GetAPIServiceConditionByType(retried, apiregistration.Available)
+	if condition == nil || condition.Status != apiregistration.ConditionTrue {
+		t.Errorf("expected the retried update to still carry the Available condition, got %#v", retried.Status.Conditions)
+	}
+}
+
+// TestAddTransitionHandler checks that every handler registered via AddTransitionHandler fires on
+// a real Available condition transition, and that a panicking handler doesn't stop the others
+// from firing or crash sync().
+func TestAddTransitionHandler(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	apiService.Spec.Service = nil
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+	}
+
+	var mu sync.Mutex
+	var firstCalls, secondCalls int
+	done := make(chan struct{}, 2)
+
+	c.AddTransitionHandler(func(old, new apiregistration.APIServiceCondition) {
+		defer func() { done <- struct{}{} }()
+		mu.Lock()
+		defer mu.Unlock()
+		firstCalls++
+		if old.Status != apiregistration.ConditionUnknown {
+			t.Errorf("expected old.Status %v for a brand new APIService, got %v", apiregistration.ConditionUnknown, old.Status)
+		}
+		if new.Status != apiregistration.ConditionTrue {
+			t.Errorf("expected new.Status %v, got %v", apiregistration.ConditionTrue, new.Status)
+		}
+		panic("a misbehaving handler should not take down sync() or the other handler")
+	})
+	c.AddTransitionHandler(func(old, new apiregistration.APIServiceCondition) {
+		defer func() { done <- struct{}{} }()
+		mu.Lock()
+		defer mu.Unlock()
+		secondCalls++
+	})
+
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for both handlers to fire")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if firstCalls != 1 || secondCalls != 1 {
+		t.Errorf("expected both handlers to fire exactly once, got %d and %d", firstCalls, secondCalls)
+	}
+}
+
+// TestSyncServiceNotFoundInTerminatingNamespace checks that a missing backing Service is
+// reported with the more specific NamespaceTerminating reason when its namespace is being
+// deleted, instead of the generic ServiceNotFound.
+func TestSyncServiceNotFoundInTerminatingNamespace(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	namespaceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	namespaceIndexer.Add(&v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Status:     v1.NamespaceStatus{Phase: v1.NamespaceTerminating},
+	})
+
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})),
+		namespaceLister:  v1listers.NewNamespaceLister(namespaceIndexer),
+	}
+	c.sync("remote.group")
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Reason != "NamespaceTerminating" {
+		t.Errorf("expected reason NamespaceTerminating, got %v", condition.Reason)
+	}
+}
+
+// TestSyncHandlesAPIServiceBecomingLocal guards against a panic if an APIService that used to
+// have a backing Service is updated to drop Spec.Service (becoming a local APIService): sync
+// must take the "local" branch instead of dereferencing the now-nil Service reference.
+func TestSyncHandlesAPIServiceBecomingLocal(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	apiService.Spec.Service = nil
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+	}
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Reason != "Local" {
+		t.Errorf("expected a nil Spec.Service to be treated as local, got reason %v", condition.Reason)
+	}
+}
+
+// TestSyncLocalAPIServiceHealthCheckFailure covers the optional readiness hook for local
+// APIServices: when configured and failing, it should override the usual always-available
+// "Local" outcome.
+func TestSyncLocalAPIServiceHealthCheckFailure(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	apiService.Spec.Service = nil
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+	}
+	c.SetLocalDelegateHealthChecker(func() error {
+		return fmt.Errorf("not ready yet")
+	})
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Status != apiregistration.ConditionFalse {
+		t.Errorf("expected a failing local health check to mark the APIService unavailable, got status %v", condition.Status)
+	}
+	if condition.Reason != "LocalHealthCheckFailed" {
+		t.Errorf("expected reason LocalHealthCheckFailed, got %v", condition.Reason)
+	}
+}
+
+// TestSyncTreatUnauthorizedAsAvailable covers the policy knob that lets a 401/403 from the
+// discovery probe still count as "available", since it proves the backend is reachable.
+func TestSyncTreatUnauthorizedAsAvailable(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	service := newService("foo", "bar")
+	endpoints := newEndpointsWithAddress("foo", "bar")
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	c := AvailableConditionController{
+		apiServiceClient:             fakeClient.Apiregistration(),
+		apiServiceLister:             listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:                v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:              v1listers.NewEndpointsLister(endpointsIndexer),
+		serviceResolver:              &fakeServiceResolver{url: &url.URL{Scheme: "https", Host: "1.2.3.4:443"}},
+		prober:                       &fakeProber{err: &ErrProbeUnauthorized{StatusCode: 401}},
+		TreatUnauthorizedAsAvailable: true,
+	}
+	c.sync("remote.group")
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Status != apiregistration.ConditionTrue {
+		t.Errorf("expected a 401 to still be treated as available, got status %v reason %v", condition.Status, condition.Reason)
+	}
+}
+
+// TestSyncWithProber exercises the discovery probe path directly, using a fake Prober in
+// place of a real HTTP stack, so both the "Passed" and "FailedDiscoveryCheck" outcomes can
+// be asserted without network access.
+func TestSyncWithProber(t *testing.T) {
+	resolvedURL := &url.URL{Scheme: "https", Host: "1.2.3.4:443"}
+
+	tests := []struct {
+		name                 string
+		proberErr            error
+		expectedStatus       apiregistration.ConditionStatus
+		expectedReason       string
+		expectedMessageEmpty bool
+	}{
+		{
+			name:           "probe succeeds",
+			proberErr:      nil,
+			expectedStatus: apiregistration.ConditionTrue,
+			expectedReason: "Passed",
+		},
+		{
+			name:           "probe fails",
+			proberErr:      fmt.Errorf("connection refused"),
+			expectedStatus: apiregistration.ConditionFalse,
+			expectedReason: "FailedDiscoveryCheck",
+		},
+		{
+			name: "probe fails due to dns resolution",
+			proberErr: &url.Error{
+				Op:  "Get",
+				URL: resolvedURL.String(),
+				Err: &net.OpError{
+					Op:  "dial",
+					Err: &net.DNSError{Err: "no such host", Name: "foo.bar.svc", IsNotFound: true},
+				},
+			},
+			expectedStatus: apiregistration.ConditionFalse,
+			expectedReason: "ServiceDNSResolutionFailed",
+		},
+		{
+			name: "probe fails due to http/2 goaway",
+			proberErr: &url.Error{
+				Op:  "Get",
+				URL: resolvedURL.String(),
+				Err: http2.GoAwayError{LastStreamID: 7, ErrCode: http2.ErrCodeEnhanceYourCalm},
+			},
+			expectedStatus: apiregistration.ConditionFalse,
+			expectedReason: "DiscoveryProtocolError",
+		},
+		{
+			name:           "probe fails with 404",
+			proberErr:      &ErrProbeFailed{StatusCode: http.StatusNotFound},
+			expectedStatus: apiregistration.ConditionFalse,
+			expectedReason: "DiscoveryNotFound",
+		},
+		{
+			name:           "probe fails with 500",
+			proberErr:      &ErrProbeFailed{StatusCode: http.StatusInternalServerError},
+			expectedStatus: apiregistration.ConditionFalse,
+			expectedReason: "DiscoveryServerError",
+		},
+		{
+			name:           "probe succeeds but doesn't advertise the expected group/version",
+			proberErr:      &ErrGroupVersionNotAdvertised{Expected: "remote.group/v1", Actual: "other.group/v1"},
+			expectedStatus: apiregistration.ConditionFalse,
+			expectedReason: "GroupVersionNotAdvertised",
+		},
+	}
+
+	for _, tc := range tests {
+		apiService := newRemoteAPIService("remote.group")
+		service := newService("foo", "bar")
+		endpoints := newEndpointsWithAddress("foo", "bar")
+
+		fakeClient := fake.NewSimpleClientset(apiService)
+		apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		apiServiceIndexer.Add(apiService)
+		serviceIndexer.Add(service)
+		endpointsIndexer.Add(endpoints)
+
+		c := AvailableConditionController{
+			apiServiceClient: fakeClient.Apiregistration(),
+			apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+			serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+			endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+			serviceResolver:  &fakeServiceResolver{url: resolvedURL},
+			prober:           &fakeProber{err: tc.proberErr},
 		}
-		if e, a := tc.expectedAvailability.Status, condition.Status; e != a {
-			t.Errorf("%v expected %v, got %#v", tc.name, e, condition)
+		c.sync("remote.group")
+
+		action, ok := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+		if !ok {
+			t.Errorf("%v: expected an update action", tc.name)
+			continue
 		}
-		if e, a := tc.expectedAvailability.Reason, condition.Reason; e != a {
-			t.Errorf("%v expected %v, got %#v", tc.name, e, condition)
+		condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+		if e, a := tc.expectedStatus, condition.Status; e != a {
+			t.Errorf("%v: expected status %v, got %v", tc.name, e, a)
 		}
-		if e, a := tc.expectedAvailability.Message, condition.Message; e != a {
-			t.Errorf("%v expected %v, got %#v", tc.name, e, condition)
+		if e, a := tc.expectedReason, condition.Reason; e != a {
+			t.Errorf("%v: expected reason %v, got %v", tc.name, e, a)
 		}
 	}
 }
+
+// TestSyncIncludesProbedPort checks that the resolved numeric target port -- what a named
+// discovery port (e.g. "https") actually resolved to -- shows up in the Passed message, so
+// operators can confirm which port was probed without having to cross-reference the Service.
+func TestSyncIncludesProbedPort(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	service := newService("foo", "bar")
+	endpoints := newEndpointsWithAddress("foo", "bar")
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		// simulates a named discovery port (e.g. "https") having resolved to 8443.
+		serviceResolver: &fakeServiceResolver{url: &url.URL{Scheme: "https", Host: "1.2.3.4:8443"}},
+		prober:          &fakeProber{},
+	}
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if !strings.Contains(condition.Message, "probed port 8443") {
+		t.Errorf("expected the resolved port to appear in the message, got %q", condition.Message)
+	}
+}
+
+// TestSyncServiceResolutionFailure checks that a failing ServiceResolver -- which previously left
+// the APIService's condition untouched -- now marks it unavailable and names the port that
+// couldn't be reached, instead of silently returning an error only visible in controller logs.
+func TestSyncServiceResolutionFailure(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	service := newService("foo", "bar")
+	endpoints := newEndpointsWithAddress("foo", "bar")
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		serviceResolver:  &failingServiceResolver{err: fmt.Errorf("no endpoints available for service %q", "bar")},
+	}
+	if err := c.sync("remote.group"); err == nil {
+		t.Fatalf("expected sync to return the resolution error to trigger a requeue")
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Status != apiregistration.ConditionFalse {
+		t.Errorf("expected a resolution failure to mark the APIService unavailable, got status %v", condition.Status)
+	}
+	if condition.Reason != "FailedDiscoveryCheck" {
+		t.Errorf("expected reason FailedDiscoveryCheck, got %v", condition.Reason)
+	}
+	if !strings.Contains(condition.Message, "port 443") {
+		t.Errorf("expected the attempted port to appear in the message, got %q", condition.Message)
+	}
+}
+
+// TestSyncFallsBackToHealthyService checks that when the primary backing Service is unavailable,
+// sync probes Spec.FallbackServices in order and reports Available as True from the first one
+// that's healthy, naming both the failed primary and the fallback that was used.
+func TestSyncFallsBackToHealthyService(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	apiService.Spec.FallbackServices = []apiregistration.ServiceReference{
+		{Namespace: "foo", Name: "baz"},
+	}
+	fallbackService := newService("foo", "baz")
+	fallbackEndpoints := newEndpointsWithAddress("foo", "baz")
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	// the primary Service ("bar") is deliberately left out of the lister to simulate it being
+	// unavailable; only the fallback ("baz") is registered.
+	serviceIndexer.Add(fallbackService)
+	endpointsIndexer.Add(fallbackEndpoints)
+
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		serviceResolver:  &fakeServiceResolver{url: &url.URL{Scheme: "https", Host: "1.2.3.4:443"}},
+		prober:           &fakeProber{},
+	}
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := apiregistration.GetAPIServiceConditionByType(action.GetObject().(*apiregistration.APIService), apiregistration.Available)
+	if condition == nil {
+		t.Fatalf("expected an Available condition, got %#v", action.GetObject())
+	}
+	if condition.Status != apiregistration.ConditionTrue {
+		t.Errorf("expected a healthy fallback to mark the APIService available, got status %v: %v", condition.Status, condition.Message)
+	}
+	if !strings.Contains(condition.Message, "primary service/bar") || !strings.Contains(condition.Message, "ServiceNotFound") {
+		t.Errorf("expected the message to explain the primary's failure, got %q", condition.Message)
+	}
+	if !strings.Contains(condition.Message, "fallback service/baz") {
+		t.Errorf("expected the message to name the fallback that was used, got %q", condition.Message)
+	}
+}
+
+// TestGetAPIServicesForMatchesFallbackServices checks that getAPIServicesFor -- which drives the
+// Service/Endpoints informer handlers' decision to enqueue an APIService for a resync -- matches
+// on Spec.FallbackServices as well as the primary Spec.Service. Without this, a fallback Service
+// becoming healthy again wouldn't get picked up until the next periodic resync.
+func TestGetAPIServicesForMatchesFallbackServices(t *testing.T) {
+	primary := newRemoteAPIService("primary.group")
+	primary.Spec.Service = &apiregistration.ServiceReference{Namespace: "foo", Name: "bar"}
+	withFallback := newRemoteAPIService("fallback.group")
+	withFallback.Spec.Service = &apiregistration.ServiceReference{Namespace: "foo", Name: "other"}
+	withFallback.Spec.FallbackServices = []apiregistration.ServiceReference{
+		{Namespace: "foo", Name: "baz"},
+	}
+	local := newLocalAPIService("local.group")
+
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(primary)
+	apiServiceIndexer.Add(withFallback)
+	apiServiceIndexer.Add(local)
+
+	c := AvailableConditionController{apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer)}
+
+	fallbackService := newService("foo", "baz")
+	matches := c.getAPIServicesFor(fallbackService)
+	if len(matches) != 1 || matches[0].Name != withFallback.Name {
+		t.Errorf("expected a change to fallback service/baz to match only %q, got %v", withFallback.Name, matches)
+	}
+
+	primaryService := newService("foo", "bar")
+	matches = c.getAPIServicesFor(primaryService)
+	if len(matches) != 1 || matches[0].Name != primary.Name {
+		t.Errorf("expected a change to primary service/bar to match only %q, got %v", primary.Name, matches)
+	}
+}
+
+// TestSyncInvalidCABundle checks that an APIService with an unparseable CABundle is marked
+// unavailable with a specific reason, and that the probe (which would otherwise fail with an
+// opaque TLS error) is never attempted.
+func TestSyncInvalidCABundle(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	apiService.Spec.CABundle = []byte("not a valid PEM certificate")
+	service := newService("foo", "bar")
+	endpoints := newEndpointsWithAddress("foo", "bar")
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	prober := &fakeProber{}
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		serviceResolver:  &fakeServiceResolver{url: &url.URL{Scheme: "https", Host: "1.2.3.4:443"}},
+		prober:           prober,
+	}
+	if err := c.sync("remote.group"); err == nil {
+		t.Fatalf("expected sync to return an error to trigger a requeue")
+	}
+
+	if prober.called {
+		t.Errorf("expected the probe to be skipped for an unparseable CABundle")
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Status != apiregistration.ConditionFalse {
+		t.Errorf("expected an invalid CABundle to mark the APIService unavailable, got status %v", condition.Status)
+	}
+	if condition.Reason != string(apiregistration.AvailabilityReasonInvalidCABundle) {
+		t.Errorf("expected reason InvalidCABundle, got %v", condition.Reason)
+	}
+}
+
+// TestSyncMissingGroupVersion checks that an APIService with an empty group/version is reported
+// unavailable with reason MissingGroupVersion, and that sync never proceeds to a confusing
+// discovery failure for a backend that can't be meaningfully probed.
+func TestSyncMissingGroupVersion(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	apiService.Spec.Group = ""
+	apiService.Spec.Version = ""
+	service := newService("foo", "bar")
+	endpoints := newEndpointsWithAddress("foo", "bar")
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	prober := &fakeProber{}
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		serviceResolver:  &fakeServiceResolver{url: &url.URL{Scheme: "https", Host: "1.2.3.4:443"}},
+		prober:           prober,
+	}
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if prober.called {
+		t.Errorf("expected the probe to be skipped for an APIService with no group/version")
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Status != apiregistration.ConditionFalse {
+		t.Errorf("expected a missing group/version to mark the APIService unavailable, got status %v", condition.Status)
+	}
+	if condition.Reason != string(apiregistration.AvailabilityReasonMissingGroupVersion) {
+		t.Errorf("expected reason MissingGroupVersion, got %v", condition.Reason)
+	}
+}
+
+// TestSyncNoLocalZoneEndpoints checks that, with LocalZone and NodeZoneLookup configured, a
+// Service whose only ready endpoint resolves to a remote zone is reported unavailable with
+// reason NoLocalZoneEndpoints, even though the endpoint is otherwise ready.
+func TestSyncNoLocalZoneEndpoints(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	service := newService("foo", "bar")
+	remoteNode := "node-in-zone-b"
+	endpoints := &v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+		Subsets: []v1.EndpointSubset{
+			{
+				Addresses: []v1.EndpointAddress{
+					{IP: "10.0.0.1", NodeName: &remoteNode},
+				},
+			},
+		},
+	}
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	prober := &fakeProber{}
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		serviceResolver:  &fakeServiceResolver{url: &url.URL{Scheme: "https", Host: "1.2.3.4:443"}},
+		prober:           prober,
+		LocalZone:        "zone-a",
+		NodeZoneLookup: func(nodeName string) (string, bool) {
+			return "zone-b", true
+		},
+	}
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if prober.called {
+		t.Errorf("expected the probe to be skipped when no ready endpoint is in the local zone")
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Status != apiregistration.ConditionFalse {
+		t.Errorf("expected a remote-zone-only endpoint to mark the APIService unavailable, got status %v", condition.Status)
+	}
+	if condition.Reason != string(apiregistration.AvailabilityReasonNoLocalZoneEndpoints) {
+		t.Errorf("expected reason NoLocalZoneEndpoints, got %v", condition.Reason)
+	}
+}
+
+// TestSyncNoReadyPorts checks that sync() reports AvailabilityReasonNoReadyPorts, and skips the
+// probe, when a Service declares ports but its ready endpoint addresses aren't served on any of
+// them -- as can happen briefly after a Service port change while endpoints is still catching up.
+func TestSyncNoReadyPorts(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	service := newService("foo", "bar")
+	service.Spec.Ports = []v1.ServicePort{{Name: "https", Port: 443}}
+	endpoints := &v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+		Subsets: []v1.EndpointSubset{
+			{
+				Addresses: []v1.EndpointAddress{{IP: "10.0.0.1"}},
+				Ports:     []v1.EndpointPort{{Name: "https", Port: 8443}},
+			},
+		},
+	}
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	prober := &fakeProber{}
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		serviceResolver:  &fakeServiceResolver{url: &url.URL{Scheme: "https", Host: "1.2.3.4:443"}},
+		prober:           prober,
+	}
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if prober.called {
+		t.Errorf("expected the probe to be skipped when no ready address serves any of the service's ports")
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Status != apiregistration.ConditionFalse {
+		t.Errorf("expected a port mismatch to mark the APIService unavailable, got status %v", condition.Status)
+	}
+	if condition.Reason != string(apiregistration.AvailabilityReasonNoReadyPorts) {
+		t.Errorf("expected reason NoReadyPorts, got %v", condition.Reason)
+	}
+	if !strings.Contains(condition.Message, "443") {
+		t.Errorf("expected the message to list the service's expected ports, got %q", condition.Message)
+	}
+}
+
+// TestSyncCombinesSimultaneousFailures checks that when a Service has more than one thing wrong
+// with it at once -- here, under EndpointReadinessPolicyRequireAll, a not-ready address and a port
+// that doesn't match any ready endpoint -- sync() reports both in the condition Message instead of
+// only whichever check happened to run first, while still picking one deterministic Reason.
+func TestSyncCombinesSimultaneousFailures(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	service := newService("foo", "bar")
+	service.Spec.Ports = []v1.ServicePort{{Name: "https", Port: 443}}
+	endpoints := &v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+		Subsets: []v1.EndpointSubset{
+			{
+				Addresses:         []v1.EndpointAddress{{IP: "10.0.0.1"}},
+				NotReadyAddresses: []v1.EndpointAddress{{IP: "10.0.0.2"}},
+				Ports:             []v1.EndpointPort{{Name: "https", Port: 8443}},
+			},
+		},
+	}
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	prober := &fakeProber{}
+	c := AvailableConditionController{
+		apiServiceClient:        fakeClient.Apiregistration(),
+		apiServiceLister:        listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:           v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:         v1listers.NewEndpointsLister(endpointsIndexer),
+		serviceResolver:         &fakeServiceResolver{url: &url.URL{Scheme: "https", Host: "1.2.3.4:443"}},
+		prober:                  prober,
+		EndpointReadinessPolicy: EndpointReadinessPolicyRequireAll,
+	}
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if prober.called {
+		t.Errorf("expected the probe to be skipped when the endpoints are unavailable")
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Status != apiregistration.ConditionFalse {
+		t.Errorf("expected the APIService to be unavailable, got status %v", condition.Status)
+	}
+	if condition.Reason != string(apiregistration.AvailabilityReasonEndpointsNotAllReady) {
+		t.Errorf("expected the readiness failure's reason to win, got %v", condition.Reason)
+	}
+	if !strings.Contains(condition.Message, "not all ready") {
+		t.Errorf("expected the message to include the readiness failure, got %q", condition.Message)
+	}
+	if !strings.Contains(condition.Message, "443") {
+		t.Errorf("expected the message to also include the port mismatch failure, got %q", condition.Message)
+	}
+	if !strings.Contains(condition.Message, "; ") {
+		t.Errorf("expected the two failures to be joined by \"; \", got %q", condition.Message)
+	}
+}
+
+// TestSyncCrossClusterServiceSkipsEndpointCheck checks that an APIService carrying
+// CrossClusterServiceAnnotation goes straight to the discovery probe, without consulting the
+// (empty) local Endpoints lister -- as a Service whose endpoints live in another cluster would
+// never have a local Endpoints object at all.
+func TestSyncCrossClusterServiceSkipsEndpointCheck(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	apiService.Annotations = map[string]string{apiregistration.CrossClusterServiceAnnotation: "true"}
+	service := newService("foo", "bar")
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	// deliberately no Endpoints added: a cross-cluster Service's endpoints never show up locally.
+
+	prober := &fakeProber{}
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		serviceResolver:  &fakeServiceResolver{url: &url.URL{Scheme: "https", Host: "1.2.3.4:443"}},
+		prober:           prober,
+	}
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !prober.called {
+		t.Errorf("expected the probe to run even though no local Endpoints exist for the cross-cluster service")
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Status != apiregistration.ConditionTrue {
+		t.Errorf("expected a successful probe to mark the cross-cluster APIService available, got status %v, reason %v", condition.Status, condition.Reason)
+	}
+}
+
+// TestSyncEndpointsTerminating checks that when every not-ready address is backed by a
+// terminating Pod, sync() reports AvailabilityReasonEndpointsTerminating instead of the more
+// generic AvailabilityReasonMissingEndpoints, and skips the probe.
+func TestSyncEndpointsTerminating(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	service := newService("foo", "bar")
+	endpoints := &v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+		Subsets: []v1.EndpointSubset{
+			{
+				NotReadyAddresses: []v1.EndpointAddress{
+					{IP: "10.0.0.1", TargetRef: &v1.ObjectReference{Kind: "Pod", Namespace: "foo", Name: "pod-1"}},
+				},
+			},
+		},
+	}
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	prober := &fakeProber{}
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		prober:           prober,
+		PodTerminatingLookup: func(namespace, name string) bool {
+			return namespace == "foo" && name == "pod-1"
+		},
+	}
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if prober.called {
+		t.Errorf("expected the probe to be skipped when all endpoints are terminating")
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Status != apiregistration.ConditionFalse {
+		t.Errorf("expected an all-terminating endpoint set to mark the APIService unavailable, got status %v", condition.Status)
+	}
+	if condition.Reason != string(apiregistration.AvailabilityReasonEndpointsTerminating) {
+		t.Errorf("expected reason EndpointsTerminating, got %v", condition.Reason)
+	}
+}
+
+// TestSyncRequiresConsecutiveSuccesses checks that with MinConsecutiveSuccesses configured, a
+// single successful probe isn't enough to flip Available to True -- the condition stays False
+// with reason AwaitingConsecutiveSuccesses until enough consecutive syncs have succeeded.
+func TestSyncRequiresConsecutiveSuccesses(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	service := newService("foo", "bar")
+	endpoints := newEndpointsWithAddress("foo", "bar")
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	c := &AvailableConditionController{
+		apiServiceClient:        fakeClient.Apiregistration(),
+		apiServiceLister:        listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:           v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:         v1listers.NewEndpointsLister(endpointsIndexer),
+		serviceResolver:         &fakeServiceResolver{url: &url.URL{Scheme: "https", Host: "1.2.3.4:443"}},
+		prober:                  &fakeProber{},
+		MinConsecutiveSuccesses: 2,
+	}
+
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error on first sync: %v", err)
+	}
+	actions := fakeClient.Actions()
+	condition := actions[len(actions)-1].(clienttesting.UpdateAction).GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Status != apiregistration.ConditionFalse {
+		t.Fatalf("expected the first successful probe to leave the APIService unavailable, got status %v", condition.Status)
+	}
+	if condition.Reason != string(apiregistration.AvailabilityReasonAwaitingConsecutiveSuccesses) {
+		t.Fatalf("expected reason AwaitingConsecutiveSuccesses, got %v", condition.Reason)
+	}
+
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error on second sync: %v", err)
+	}
+	actions = fakeClient.Actions()
+	condition = actions[len(actions)-1].(clienttesting.UpdateAction).GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Status != apiregistration.ConditionTrue {
+		t.Errorf("expected the second consecutive successful probe to mark the APIService available, got status %v", condition.Status)
+	}
+	if condition.Reason != string(apiregistration.AvailabilityReasonPassed) {
+		t.Errorf("expected reason Passed, got %v", condition.Reason)
+	}
+}
+
+// TestSyncUsesProbeHostOverride checks that an APIService annotated with
+// apiregistration.ProbeHostOverrideAnnotation passes that host through to the prober, for
+// split-horizon DNS setups where the aggregator resolves the backend via an address that doesn't
+// itself answer to the name the backend expects.
+func TestSyncUsesProbeHostOverride(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	apiService.Annotations = map[string]string{apiregistration.ProbeHostOverrideAnnotation: "remote.example.com"}
+	service := newService("foo", "bar")
+	endpoints := newEndpointsWithAddress("foo", "bar")
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	prober := &fakeProber{}
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		serviceResolver:  &fakeServiceResolver{url: &url.URL{Scheme: "https", Host: "1.2.3.4:443"}},
+		prober:           prober,
+	}
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if prober.probedHost != "remote.example.com" {
+		t.Errorf("expected the probe host override to reach the prober, got %q", prober.probedHost)
+	}
+}
+
+// TestSyncServiceNotFoundGracePeriod checks that a missing backing Service is reported with the
+// softer ServiceNotFoundPending reason while the APIService is within its configured grace
+// period, and with the hard ServiceNotFound reason once the grace period has elapsed.
+func TestSyncServiceNotFoundGracePeriod(t *testing.T) {
+	created := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		wantReason apiregistration.AvailabilityReason
+	}{
+		{
+			name:       "within grace period",
+			now:        created.Add(30 * time.Second),
+			wantReason: apiregistration.AvailabilityReasonServiceNotFoundPending,
+		},
+		{
+			name:       "after grace period",
+			now:        created.Add(2 * time.Minute),
+			wantReason: apiregistration.AvailabilityReasonServiceNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			apiService := newRemoteAPIService("remote.group")
+			apiService.CreationTimestamp = metav1.NewTime(created)
+
+			fakeClient := fake.NewSimpleClientset(apiService)
+			apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			apiServiceIndexer.Add(apiService)
+
+			c := AvailableConditionController{
+				apiServiceClient:           fakeClient.Apiregistration(),
+				apiServiceLister:           listers.NewAPIServiceLister(apiServiceIndexer),
+				serviceLister:              v1listers.NewServiceLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})),
+				ServiceNotFoundGracePeriod: time.Minute,
+			}
+			c.SetClock(clock.NewFakeClock(tc.now))
+			if err := c.sync("remote.group"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+			condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+			if condition.Status != apiregistration.ConditionFalse {
+				t.Errorf("expected a missing service to mark the APIService unavailable, got status %v", condition.Status)
+			}
+			if condition.Reason != string(tc.wantReason) {
+				t.Errorf("expected reason %v, got %v", tc.wantReason, condition.Reason)
+			}
+		})
+	}
+}
+
+// TestSyncAcceptableStatusCode checks that a discovery probe returning 204, with 204 configured
+// via SetAcceptableProbeStatusCodes, counts towards a True Available condition end-to-end through
+// sync().
+func TestSyncAcceptableStatusCode(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	service := newService("foo", "bar")
+	endpoints := newEndpointsWithAddress("foo", "bar")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	discoveryURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error parsing server URL: %v", err)
+	}
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	c := &AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		serviceResolver:  &fakeServiceResolver{url: discoveryURL},
+		prober:           &clientProber{discoveryClient: server.Client()},
+	}
+	c.SetAcceptableProbeStatusCodes([]int{http.StatusNoContent})
+
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Status != apiregistration.ConditionTrue {
+		t.Errorf("expected a configured 204 to be treated as available, got status %v, reason %v, message %q", condition.Status, condition.Reason, condition.Message)
+	}
+}
+
+// TestProbeURL exercises probeURL in isolation, asserting it accepts a well-formed resolved URL
+// and rejects one with no host instead of silently producing a malformed probe target.
+func TestProbeURL(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+
+	if got, err := probeURL(&url.URL{Scheme: "https", Host: "1.2.3.4:443"}, apiService); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	} else if want := "https://1.2.3.4:443"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if _, err := probeURL(&url.URL{Scheme: "https"}, apiService); err == nil {
+		t.Errorf("expected an empty-host resolved URL to be rejected")
+	}
+}
+
+// TestSyncRejectsEmptyHostResolution checks that a ServiceResolver returning a URL with no host
+// is treated as a discovery failure, instead of sync() going on to probe a malformed URL.
+func TestSyncRejectsEmptyHostResolution(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	service := newService("foo", "bar")
+	endpoints := newEndpointsWithAddress("foo", "bar")
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	prober := &fakeProber{}
+	c := AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		serviceResolver:  &fakeServiceResolver{url: &url.URL{Scheme: "https"}},
+		prober:           prober,
+	}
+	if err := c.sync("remote.group"); err == nil {
+		t.Fatalf("expected sync to return an error to trigger a requeue")
+	}
+
+	if prober.called {
+		t.Errorf("expected the probe to be skipped for an unresolvable discovery URL")
+	}
+
+	action := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+	condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	if condition.Status != apiregistration.ConditionFalse {
+		t.Errorf("expected status False, got %v", condition.Status)
+	}
+	if condition.Reason != string(apiregistration.AvailabilityReasonFailedDiscoveryCheck) {
+		t.Errorf("expected reason FailedDiscoveryCheck, got %v", condition.Reason)
+	}
+}
+
+func TestSyncEndpointReadinessPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         EndpointReadinessPolicy
+		ready          int
+		notReady       int
+		expectedStatus apiregistration.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "require any: satisfied with some not ready",
+			policy:         EndpointReadinessPolicyRequireAny,
+			ready:          1,
+			notReady:       5,
+			expectedStatus: apiregistration.ConditionTrue,
+			expectedReason: "Passed",
+		},
+		{
+			name:           "require any: fails with zero ready",
+			policy:         EndpointReadinessPolicyRequireAny,
+			ready:          0,
+			notReady:       3,
+			expectedStatus: apiregistration.ConditionFalse,
+			expectedReason: "MissingEndpoints",
+		},
+		{
+			name:           "require majority: satisfied",
+			policy:         EndpointReadinessPolicyRequireMajority,
+			ready:          3,
+			notReady:       2,
+			expectedStatus: apiregistration.ConditionTrue,
+			expectedReason: "Passed",
+		},
+		{
+			name:           "require majority: fails with a tie",
+			policy:         EndpointReadinessPolicyRequireMajority,
+			ready:          2,
+			notReady:       2,
+			expectedStatus: apiregistration.ConditionFalse,
+			expectedReason: "EndpointsNotMajorityReady",
+		},
+		{
+			name:           "require all: satisfied",
+			policy:         EndpointReadinessPolicyRequireAll,
+			ready:          4,
+			notReady:       0,
+			expectedStatus: apiregistration.ConditionTrue,
+			expectedReason: "Passed",
+		},
+		{
+			name:           "require all: fails with any not ready",
+			policy:         EndpointReadinessPolicyRequireAll,
+			ready:          4,
+			notReady:       1,
+			expectedStatus: apiregistration.ConditionFalse,
+			expectedReason: "EndpointsNotAllReady",
+		},
+	}
+
+	for _, tc := range tests {
+		apiService := newRemoteAPIService("remote.group")
+		service := newService("foo", "bar")
+		endpoints := newEndpointsWithReadyAndNotReady("foo", "bar", tc.ready, tc.notReady)
+
+		fakeClient := fake.NewSimpleClientset(apiService)
+		apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		apiServiceIndexer.Add(apiService)
+		serviceIndexer.Add(service)
+		endpointsIndexer.Add(endpoints)
+
+		c := AvailableConditionController{
+			apiServiceClient:        fakeClient.Apiregistration(),
+			apiServiceLister:        listers.NewAPIServiceLister(apiServiceIndexer),
+			serviceLister:           v1listers.NewServiceLister(serviceIndexer),
+			endpointsLister:         v1listers.NewEndpointsLister(endpointsIndexer),
+			EndpointReadinessPolicy: tc.policy,
+		}
+		if err := c.sync("remote.group"); err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.name, err)
+			continue
+		}
+
+		action, ok := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+		if !ok {
+			t.Errorf("%v: expected an update action", tc.name)
+			continue
+		}
+		condition := action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+		if e, a := tc.expectedStatus, condition.Status; e != a {
+			t.Errorf("%v: expected status %v, got %v", tc.name, e, a)
+		}
+		if e, a := tc.expectedReason, condition.Reason; e != a {
+			t.Errorf("%v: expected reason %v, got %v", tc.name, e, a)
+		}
+	}
+}
+
+// TestSyncSetsDegradedCondition checks that sync reports Degraded alongside Available, so a
+// partially-ready-but-still-routable service can be told apart from a fully healthy one without
+// either being reported as outright unavailable.
+func TestSyncSetsDegradedCondition(t *testing.T) {
+	tests := []struct {
+		name             string
+		ready, notReady  int
+		expectedDegraded apiregistration.ConditionStatus
+		expectedReason   string
+	}{
+		{
+			name:             "fully ready",
+			ready:            3,
+			notReady:         0,
+			expectedDegraded: apiregistration.ConditionFalse,
+			expectedReason:   "EndpointsFullyReady",
+		},
+		{
+			name:             "partially ready",
+			ready:            2,
+			notReady:         1,
+			expectedDegraded: apiregistration.ConditionTrue,
+			expectedReason:   "EndpointsPartiallyReady",
+		},
+	}
+
+	for _, tc := range tests {
+		apiService := newRemoteAPIService("remote.group")
+		service := newService("foo", "bar")
+		endpoints := newEndpointsWithReadyAndNotReady("foo", "bar", tc.ready, tc.notReady)
+
+		fakeClient := fake.NewSimpleClientset(apiService)
+		apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		apiServiceIndexer.Add(apiService)
+		serviceIndexer.Add(service)
+		endpointsIndexer.Add(endpoints)
+
+		c := AvailableConditionController{
+			apiServiceClient: fakeClient.Apiregistration(),
+			apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+			serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+			endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		}
+		if err := c.sync("remote.group"); err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.name, err)
+			continue
+		}
+
+		action, ok := fakeClient.Actions()[0].(clienttesting.UpdateAction)
+		if !ok {
+			t.Errorf("%v: expected an update action", tc.name)
+			continue
+		}
+		updated := action.GetObject().(*apiregistration.APIService)
+		available := apiregistration.GetAPIServiceConditionByType(updated, apiregistration.Available)
+		degraded := apiregistration.GetAPIServiceConditionByType(updated, apiregistration.Degraded)
+		if available == nil || available.Status != apiregistration.ConditionTrue {
+			t.Errorf("%v: expected Available=True, got %+v", tc.name, available)
+		}
+		if degraded == nil {
+			t.Fatalf("%v: expected a Degraded condition to be set", tc.name)
+		}
+		if degraded.Status != tc.expectedDegraded {
+			t.Errorf("%v: expected Degraded status %v, got %v", tc.name, tc.expectedDegraded, degraded.Status)
+		}
+		if degraded.Reason != tc.expectedReason {
+			t.Errorf("%v: expected Degraded reason %v, got %v", tc.name, tc.expectedReason, degraded.Reason)
+		}
+	}
+}
+
+// TestClientProberHandlesGzipResponse exercises clientProber.Probe against a real HTTP server
+// that gzips its discovery response, asserting the probe doesn't mistake the compressed body
+// for a failure.
+func TestClientProberHandlesGzipResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusOK)
+		gzipWriter := gzip.NewWriter(w)
+		gzipWriter.Write([]byte(`{"paths":["/apis"]}`))
+		gzipWriter.Close()
+	}))
+	defer server.Close()
+
+	discoveryURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error parsing server URL: %v", err)
+	}
+
+	prober := &clientProber{discoveryClient: server.Client()}
+	if err := prober.Probe(discoveryURL, nil, "", ""); err != nil {
+		t.Errorf("expected the gzip-encoded discovery response to probe successfully, got: %v", err)
+	}
+}
+
+// TestClientProberSendsProbeHostOverride exercises clientProber.Probe against a real HTTP server,
+// asserting that a non-empty probeHost is sent as the request's Host header rather than the
+// discovery URL's own host.
+func TestClientProberSendsProbeHostOverride(t *testing.T) {
+	var gotHost string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	discoveryURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error parsing server URL: %v", err)
+	}
+
+	prober := &clientProber{discoveryClient: server.Client()}
+	if err := prober.Probe(discoveryURL, nil, "remote.example.com", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotHost != "remote.example.com" {
+		t.Errorf("expected the request Host header to be overridden to %q, got %q", "remote.example.com", gotHost)
+	}
+}
+
+// TestClientProberProbeContextAbortsOnCancel checks that canceling ctx aborts an in-flight
+// ProbeContext call well before the 6-second insurance timeout would otherwise fire.
+func TestClientProberProbeContextAbortsOnCancel(t *testing.T) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	discoveryURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error parsing server URL: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	prober := &clientProber{discoveryClient: server.Client()}
+	go func() {
+		errCh <- prober.ProbeContext(ctx, discoveryURL, nil, "", "")
+	}()
+
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected ProbeContext to abort promptly after the context was canceled")
+	}
+}
+
+// TestClientProberAcceptableStatusCodes exercises clientProber.Probe against a real HTTP server
+// returning 304, a status code outside the default 200-299 success range, asserting it fails by
+// default but succeeds once explicitly added via AcceptableStatusCodes.
+func TestClientProberAcceptableStatusCodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	discoveryURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error parsing server URL: %v", err)
+	}
+
+	defaultProber := &clientProber{discoveryClient: server.Client()}
+	if err := defaultProber.Probe(discoveryURL, nil, "", ""); err == nil {
+		t.Fatalf("expected a 304 response to fail the probe by default")
+	}
+
+	configuredProber := &clientProber{discoveryClient: server.Client(), AcceptableStatusCodes: []int{http.StatusNotModified}}
+	if err := configuredProber.Probe(discoveryURL, nil, "", ""); err != nil {
+		t.Errorf("expected a 304 response to succeed once configured as acceptable, got: %v", err)
+	}
+}
+
+// TestClientProberRoutesThroughConfiguredProxy exercises clientProber.Probe against a plain HTTP
+// discovery URL with a configured Proxy, asserting the request actually reaches the fake proxy
+// server rather than the discovery URL's host directly.
+func TestClientProberRoutesThroughConfiguredProxy(t *testing.T) {
+	var sawRequestURI string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawRequestURI = r.RequestURI
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatalf("unexpected error parsing proxy URL: %v", err)
+	}
+
+	// an address nothing is listening on: if the probe reaches it directly instead of via the
+	// proxy, the probe fails and sawRequestURI stays empty.
+	discoveryURL := &url.URL{Scheme: "http", Host: "127.0.0.1:1", Path: "/apis"}
+
+	prober := &clientProber{
+		discoveryClient: &http.Client{},
+		Proxy:           http.ProxyURL(proxyURL),
+	}
+	if err := prober.Probe(discoveryURL, &tls.Config{InsecureSkipVerify: true}, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sawRequestURI != discoveryURL.String() {
+		t.Errorf("expected the proxy to receive a request for %q, got %q", discoveryURL.String(), sawRequestURI)
+	}
+}
+
+// TestClientProberCapturesFailureHeaders exercises clientProber.Probe against a real HTTP server
+// returning a 503 with a Retry-After header, asserting the header value ends up in the
+// returned error's message for operators debugging the failure.
+func TestClientProberCapturesFailureHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	discoveryURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error parsing server URL: %v", err)
+	}
+
+	prober := &clientProber{discoveryClient: server.Client()}
+	err = prober.Probe(discoveryURL, nil, "", "")
+	if err == nil {
+		t.Fatalf("expected a 503 response to fail the probe")
+	}
+	probeFailed, ok := err.(*ErrProbeFailed)
+	if !ok {
+		t.Fatalf("expected *ErrProbeFailed, got %T: %v", err, err)
+	}
+	if probeFailed.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected StatusCode %d, got %d", http.StatusServiceUnavailable, probeFailed.StatusCode)
+	}
+	if !strings.Contains(err.Error(), "Retry-After: 120") {
+		t.Errorf("expected the Retry-After header to appear in the error message, got %q", err.Error())
+	}
+}
+
+// TestClientProberDoesNotFollowRedirect exercises clientProber.Probe against a discovery endpoint
+// that responds with a 302 to an unrelated login page, asserting the probe reports
+// ErrProbeRedirect with the Location header rather than following the redirect.
+func TestClientProberDoesNotFollowRedirect(t *testing.T) {
+	var loginPageHit bool
+	loginPage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		loginPageHit = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer loginPage.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, loginPage.URL, http.StatusFound)
+	}))
+	defer server.Close()
+
+	discoveryURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error parsing server URL: %v", err)
+	}
+
+	client := server.Client()
+	client.CheckRedirect = noFollowRedirects
+	prober := &clientProber{discoveryClient: client}
+	err = prober.Probe(discoveryURL, nil, "", "")
+	if err == nil {
+		t.Fatalf("expected a 302 response to fail the probe")
+	}
+	redirect, ok := err.(*ErrProbeRedirect)
+	if !ok {
+		t.Fatalf("expected *ErrProbeRedirect, got %T: %v", err, err)
+	}
+	if redirect.StatusCode != http.StatusFound {
+		t.Errorf("expected StatusCode %d, got %d", http.StatusFound, redirect.StatusCode)
+	}
+	if redirect.Location != loginPage.URL {
+		t.Errorf("expected Location %q, got %q", loginPage.URL, redirect.Location)
+	}
+	if loginPageHit {
+		t.Errorf("expected the probe not to follow the redirect to the login page")
+	}
+}
+
+// TestClientProberClassifiesConnectionRefused exercises clientProber.Probe against a fake dialer
+// that immediately reports ECONNREFUSED, asserting the resulting error is recognized as
+// connection-refused and not mistaken for a timeout.
+func TestClientProberClassifiesConnectionRefused(t *testing.T) {
+	prober := &clientProber{
+		discoveryClient: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+					return nil, &net.OpError{Op: "dial", Net: network, Err: &os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED}}
+				},
+			},
+		},
+	}
+
+	err := prober.Probe(&url.URL{Scheme: "https", Host: "127.0.0.1:1"}, nil, "", "")
+	if err == nil {
+		t.Fatalf("expected a refused dial to fail the probe")
+	}
+	if _, ok := rootConnectionRefusedError(err); !ok {
+		t.Fatalf("expected rootConnectionRefusedError to recognize %v (%T)", err, err)
+	}
+	if isProbeTimeout(err) {
+		t.Errorf("a connection-refused error must not also classify as a timeout")
+	}
+}
+
+// TestClientProberClassifiesTimeout exercises clientProber.Probe against a fake dialer that hangs
+// past the http.Client's own deadline, asserting the resulting error is recognized as a timeout
+// and not mistaken for connection-refused.
+func TestClientProberClassifiesTimeout(t *testing.T) {
+	prober := &clientProber{
+		discoveryClient: &http.Client{
+			Timeout: 10 * time.Millisecond,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				},
+			},
+		},
+	}
+
+	err := prober.Probe(&url.URL{Scheme: "https", Host: "127.0.0.1:1"}, nil, "", "")
+	if err == nil {
+		t.Fatalf("expected a hung dial to fail the probe")
+	}
+	if !isProbeTimeout(err) {
+		t.Fatalf("expected isProbeTimeout to recognize %v (%T)", err, err)
+	}
+	if _, ok := rootConnectionRefusedError(err); ok {
+		t.Errorf("a timeout must not also classify as connection-refused")
+	}
+}
+
+// TestClientProberRequiredContentType exercises clientProber.Probe against a real HTTP server
+// returning a 200 with an HTML body -- e.g. a login redirect page -- asserting that a configured
+// RequiredContentType catches it instead of treating the 2xx status alone as success.
+func TestClientProberRequiredContentType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("<html><body>please log in</body></html>"))
+	}))
+	defer server.Close()
+
+	discoveryURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error parsing server URL: %v", err)
+	}
+
+	prober := &clientProber{discoveryClient: server.Client(), RequiredContentType: "application/json"}
+	err = prober.Probe(discoveryURL, nil, "", "")
+	if err == nil {
+		t.Fatalf("expected a text/html response to fail the probe when application/json is required")
+	}
+	badContentType, ok := err.(*ErrProbeBadContentType)
+	if !ok {
+		t.Fatalf("expected *ErrProbeBadContentType, got %T: %v", err, err)
+	}
+	if badContentType.ContentType != "text/html; charset=utf-8" {
+		t.Errorf("expected ContentType %q, got %q", "text/html; charset=utf-8", badContentType.ContentType)
+	}
+
+	// the same server with no RequiredContentType configured must still pass.
+	if err := (&clientProber{discoveryClient: server.Client()}).Probe(discoveryURL, nil, "", ""); err != nil {
+		t.Errorf("expected the probe to succeed without a RequiredContentType, got: %v", err)
+	}
+}
+
+// TestClientProberCustomHeaders exercises clientProber.Probe against a fake transport, asserting
+// that headers configured via SetProbeHeaders (or set directly on clientProber.Headers) reach the
+// probe request verbatim, for backends that require an identity or routing header to serve
+// discovery.
+func TestClientProberCustomHeaders(t *testing.T) {
+	var gotHeader http.Header
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		gotHeader = req.Header
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}, nil
+	})
+
+	headers := http.Header{"X-Tenant-Id": []string{"acme"}, "X-Extra": []string{"one", "two"}}
+	prober := &clientProber{discoveryClient: &http.Client{Transport: transport}, Headers: headers}
+
+	c := &AvailableConditionController{prober: prober}
+	c.SetProbeHeaders(headers)
+
+	discoveryURL := &url.URL{Scheme: "https", Host: "backend.example.com"}
+	if err := prober.Probe(discoveryURL, nil, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := gotHeader.Get("X-Tenant-Id"); got != "acme" {
+		t.Errorf("expected X-Tenant-Id: acme, got %q", got)
+	}
+	if got := gotHeader["X-Extra"]; !reflect.DeepEqual(got, []string{"one", "two"}) {
+		t.Errorf("expected X-Extra: [one two], got %v", got)
+	}
+}
+
+// TestClientProberValidatesAdvertisedGroupVersion checks that a non-empty expectedGroupVersion
+// makes clientProber.Probe fail with ErrGroupVersionNotAdvertised when the discovery document
+// doesn't advertise it, and succeed when it does.
+func TestClientProberValidatesAdvertisedGroupVersion(t *testing.T) {
+	newProber := func(body string) *clientProber {
+		transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+			}, nil
+		})
+		return &clientProber{discoveryClient: &http.Client{Transport: transport}}
+	}
+	discoveryURL := &url.URL{Scheme: "https", Host: "backend.example.com"}
+
+	t.Run("group/version missing", func(t *testing.T) {
+		prober := newProber(`{"kind":"APIResourceList","groupVersion":"other.group/v1","resources":[]}`)
+		err := prober.Probe(discoveryURL, nil, "", "remote.group/v1")
+		notAdvertised, ok := err.(*ErrGroupVersionNotAdvertised)
+		if !ok {
+			t.Fatalf("expected ErrGroupVersionNotAdvertised, got %v (%T)", err, err)
+		}
+		if notAdvertised.Expected != "remote.group/v1" || notAdvertised.Actual != "other.group/v1" {
+			t.Errorf("unexpected error contents: %+v", notAdvertised)
+		}
+	})
+
+	t.Run("group/version present", func(t *testing.T) {
+		prober := newProber(`{"kind":"APIResourceList","groupVersion":"remote.group/v1","resources":[]}`)
+		if err := prober.Probe(discoveryURL, nil, "", "remote.group/v1"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("no expectation configured", func(t *testing.T) {
+		prober := newProber(`{"kind":"APIResourceList","groupVersion":"other.group/v1","resources":[]}`)
+		if err := prober.Probe(discoveryURL, nil, "", ""); err != nil {
+			t.Errorf("expected the check to be skipped with an empty expectedGroupVersion, got: %v", err)
+		}
+	})
+}
+
+// TestSetProbeTransportLimits checks that SetProbeTransportLimits reaches both the transport
+// ProbeContext builds per TLS request and clientProber's own base transport, and that it's a
+// no-op for a custom Prober.
+func TestSetProbeTransportLimits(t *testing.T) {
+	prober := &clientProber{discoveryClient: &http.Client{Transport: &http.Transport{}}}
+	c := &AvailableConditionController{prober: prober}
+
+	c.SetProbeTransportLimits(7, 42*time.Second)
+
+	if prober.MaxIdleConnsPerHost != 7 {
+		t.Errorf("expected MaxIdleConnsPerHost 7, got %d", prober.MaxIdleConnsPerHost)
+	}
+	if prober.IdleConnTimeout != 42*time.Second {
+		t.Errorf("expected IdleConnTimeout 42s, got %v", prober.IdleConnTimeout)
+	}
+
+	built := prober.buildTransport(&tls.Config{})
+	if built.MaxIdleConnsPerHost != 7 {
+		t.Errorf("expected the constructed transport's MaxIdleConnsPerHost to be 7, got %d", built.MaxIdleConnsPerHost)
+	}
+	if built.IdleConnTimeout != 42*time.Second {
+		t.Errorf("expected the constructed transport's IdleConnTimeout to be 42s, got %v", built.IdleConnTimeout)
+	}
+
+	baseTransport := prober.discoveryClient.Transport.(*http.Transport)
+	if baseTransport.MaxIdleConnsPerHost != 7 || baseTransport.IdleConnTimeout != 42*time.Second {
+		t.Errorf("expected the base transport to reflect the configured limits too, got %+v", baseTransport)
+	}
+
+	// a custom Prober isn't a *clientProber, so the call must be a harmless no-op.
+	custom := &AvailableConditionController{prober: &fakeProber{}}
+	custom.SetProbeTransportLimits(3, time.Second)
+}
+
+// generateSelfSignedCertPEM returns a freshly generated, PEM-encoded self-signed certificate and
+// its matching private key, suitable for exercising tls.LoadX509KeyPair in tests; it is never
+// used to dial anything.
+func generateSelfSignedCertPEM(t *testing.T) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test-client"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certPEM, keyPEM
+}
+
+// TestSetProbeClientCertificate checks that SetProbeClientCertificate loads a client certificate
+// and reaches the transport buildTransport constructs, so a backend requiring mutual TLS is
+// presented with it during the probe's handshake, and that it's a no-op for a custom Prober.
+func TestSetProbeClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certPEM, keyPEM := generateSelfSignedCertPEM(t)
+	certPath := filepath.Join(dir, "client.crt")
+	keyPath := filepath.Join(dir, "client.key")
+	if err := ioutil.WriteFile(certPath, certPEM, 0600); err != nil {
+		t.Fatalf("failed to write cert: %v", err)
+	}
+	if err := ioutil.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	prober := &clientProber{discoveryClient: &http.Client{Transport: &http.Transport{}}}
+	c := &AvailableConditionController{prober: prober}
+
+	if err := c.SetProbeClientCertificate(certPath, keyPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prober.ClientCertificates) != 1 {
+		t.Fatalf("expected one configured client certificate, got %d", len(prober.ClientCertificates))
+	}
+
+	built := prober.buildTransport(&tls.Config{})
+	if len(built.TLSClientConfig.Certificates) != 1 || !reflect.DeepEqual(built.TLSClientConfig.Certificates[0], prober.ClientCertificates[0]) {
+		t.Errorf("expected the constructed transport's TLS config to present the configured client certificate, got %+v", built.TLSClientConfig.Certificates)
+	}
+
+	if err := c.SetProbeClientCertificate(filepath.Join(dir, "missing.crt"), keyPath); err == nil {
+		t.Errorf("expected an error loading a nonexistent certificate file")
+	}
+
+	// a custom Prober isn't a *clientProber, so the call must be a harmless no-op.
+	custom := &AvailableConditionController{prober: &fakeProber{}}
+	if err := custom.SetProbeClientCertificate(certPath, keyPath); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestProbeCircuitBreaker checks that, once CircuitBreakerThreshold consecutive probe failures
+// against a host are reached, the breaker opens and further syncs skip the probe entirely and
+// report AvailabilityReasonCircuitBreakerOpen -- until CircuitBreakerCooldown elapses, at which
+// point it half-opens to let a single trial probe through, closing again on success.
+func TestProbeCircuitBreaker(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	service := newService("foo", "bar")
+	endpoints := newEndpointsWithAddress("foo", "bar")
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	prober := &fakeProber{err: fmt.Errorf("connection refused")}
+	fakeClock := clock.NewFakeClock(time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC))
+	c := AvailableConditionController{
+		apiServiceClient:        fakeClient.Apiregistration(),
+		apiServiceLister:        listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:           v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:         v1listers.NewEndpointsLister(endpointsIndexer),
+		serviceResolver:         &fakeServiceResolver{url: &url.URL{Scheme: "https", Host: "1.2.3.4:443"}},
+		prober:                  prober,
+		CircuitBreakerThreshold: 2,
+		CircuitBreakerCooldown:  time.Minute,
+	}
+	c.SetClock(fakeClock)
+
+	latestCondition := func() apiregistration.APIServiceCondition {
+		actions := fakeClient.Actions()
+		action := actions[len(actions)-1].(clienttesting.UpdateAction)
+		return action.GetObject().(*apiregistration.APIService).Status.Conditions[0]
+	}
+
+	// two consecutive failures trip the breaker (threshold 2); both still actually probe.
+	for i := 0; i < 2; i++ {
+		prober.called = false
+		if err := c.sync("remote.group"); err == nil {
+			t.Fatalf("round %d: expected sync to return the probe error to force a requeue", i)
+		}
+		if !prober.called {
+			t.Fatalf("round %d: expected the probe to actually run before the breaker trips", i)
+		}
+	}
+	if condition := latestCondition(); condition.Reason != "FailedDiscoveryCheck" {
+		t.Fatalf("expected the tripping failure to still report the real probe failure, got reason %v", condition.Reason)
+	}
+
+	// the breaker is now open: the next sync must skip the probe entirely.
+	prober.called = false
+	if err := c.sync("remote.group"); err == nil {
+		t.Fatalf("expected sync to still return an error while the breaker is open")
+	}
+	if prober.called {
+		t.Errorf("expected the probe to be skipped while the circuit breaker is open")
+	}
+	condition := latestCondition()
+	if condition.Status != apiregistration.ConditionFalse {
+		t.Errorf("expected status False while the breaker is open, got %v", condition.Status)
+	}
+	if condition.Reason != string(apiregistration.AvailabilityReasonCircuitBreakerOpen) {
+		t.Errorf("expected reason CircuitBreakerOpen, got %v", condition.Reason)
+	}
+	if !strings.Contains(condition.Message, "connection refused") {
+		t.Errorf("expected the message to carry the last known failure, got %q", condition.Message)
+	}
+
+	// once the cooldown elapses, the breaker half-opens and a successful trial probe closes it.
+	fakeClock.Step(2 * time.Minute)
+	prober.called = false
+	prober.err = nil
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !prober.called {
+		t.Errorf("expected the half-open breaker to allow a trial probe through after cooldown")
+	}
+	if condition := latestCondition(); condition.Reason != "Passed" {
+		t.Errorf("expected a successful trial probe to close the breaker and report Passed, got %v", condition.Reason)
+	}
+}
+
+func TestAPIServiceConditionEqual(t *testing.T) {
+	base := apiregistration.APIServiceCondition{
+		Type:               apiregistration.Available,
+		Status:             apiregistration.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+		Reason:             "Passed",
+		Message:            "all checks passed",
+	}
+
+	tests := []struct {
+		name      string
+		other     apiregistration.APIServiceCondition
+		wantEqual bool
+	}{
+		{
+			name: "equal except for LastTransitionTime",
+			other: apiregistration.APIServiceCondition{
+				Type:               base.Type,
+				Status:             base.Status,
+				LastTransitionTime: metav1.NewTime(base.LastTransitionTime.Add(time.Hour)),
+				Reason:             base.Reason,
+				Message:            base.Message,
+			},
+			wantEqual: true,
+		},
+		{
+			name: "different status",
+			other: apiregistration.APIServiceCondition{
+				Type:    base.Type,
+				Status:  apiregistration.ConditionFalse,
+				Reason:  base.Reason,
+				Message: base.Message,
+			},
+			wantEqual: false,
+		},
+		{
+			name: "different reason",
+			other: apiregistration.APIServiceCondition{
+				Type:    base.Type,
+				Status:  base.Status,
+				Reason:  "FailedDiscoveryCheck",
+				Message: base.Message,
+			},
+			wantEqual: false,
+		},
+		{
+			name: "different message",
+			other: apiregistration.APIServiceCondition{
+				Type:    base.Type,
+				Status:  base.Status,
+				Reason:  base.Reason,
+				Message: "something else",
+			},
+			wantEqual: false,
+		},
+	}
+
+	for _, tc := range tests {
+		if e, a := tc.wantEqual, base.Equal(tc.other); e != a {
+			t.Errorf("%v: expected Equal=%v, got %v", tc.name, e, a)
+		}
+	}
+}
+
+func TestRootDNSError(t *testing.T) {
+	dnsErr := &net.DNSError{Err: "no such host", Name: "foo.bar.svc", IsNotFound: true}
+
+	tests := []struct {
+		name      string
+		err       error
+		wantFound bool
+	}{
+		{
+			name:      "bare dns error",
+			err:       dnsErr,
+			wantFound: true,
+		},
+		{
+			name: "dns error wrapped in OpError and url.Error",
+			err: &url.Error{
+				Op:  "Get",
+				URL: "https://1.2.3.4",
+				Err: &net.OpError{Op: "dial", Err: dnsErr},
+			},
+			wantFound: true,
+		},
+		{
+			name:      "unrelated error",
+			err:       fmt.Errorf("connection refused"),
+			wantFound: false,
+		},
+	}
+
+	for _, tc := range tests {
+		got, found := rootDNSError(tc.err)
+		if found != tc.wantFound {
+			t.Errorf("%v: expected found=%v, got %v", tc.name, tc.wantFound, found)
+			continue
+		}
+		if found && got != dnsErr {
+			t.Errorf("%v: expected the wrapped *net.DNSError to be returned, got %v", tc.name, got)
+		}
+	}
+}
+
+func TestRootHTTP2Error(t *testing.T) {
+	goAwayErr := http2.GoAwayError{LastStreamID: 7, ErrCode: http2.ErrCodeEnhanceYourCalm}
+
+	tests := []struct {
+		name      string
+		err       error
+		wantFound bool
+	}{
+		{
+			name:      "bare goaway error",
+			err:       goAwayErr,
+			wantFound: true,
+		},
+		{
+			name: "goaway error wrapped in url.Error",
+			err: &url.Error{
+				Op:  "Get",
+				URL: "https://1.2.3.4",
+				Err: goAwayErr,
+			},
+			wantFound: true,
+		},
+		{
+			name:      "stream error",
+			err:       http2.StreamError{StreamID: 3, Code: http2.ErrCodeCancel},
+			wantFound: true,
+		},
+		{
+			name:      "connection error",
+			err:       http2.ConnectionError(http2.ErrCodeProtocol),
+			wantFound: true,
+		},
+		{
+			name:      "unrelated error",
+			err:       fmt.Errorf("connection refused"),
+			wantFound: false,
+		},
+	}
+
+	for _, tc := range tests {
+		got, found := rootHTTP2Error(tc.err)
+		if found != tc.wantFound {
+			t.Errorf("%v: expected found=%v, got %v", tc.name, tc.wantFound, found)
+			continue
+		}
+		if found && got == nil {
+			t.Errorf("%v: expected a non-nil HTTP/2 error to be returned", tc.name)
+		}
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestProbeRespectsMaxConcurrentProbesPerHost probes a single shared host, as multiple
+// APIServices backed by the same Service would, through a fake transport that counts how many
+// requests are in flight at once, asserting MaxConcurrentProbesPerHost is never exceeded even
+// when every probe is started at the same time. Run with -race to catch any unsynchronized
+// access to the concurrency counter.
+func TestProbeRespectsMaxConcurrentProbesPerHost(t *testing.T) {
+	const limit = 2
+	const numProbes = 8
+
+	var mu sync.Mutex
+	inFlight := 0
+	maxInFlight := 0
+
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       ioutil.NopCloser(strings.NewReader("{}")),
+		}, nil
+	})
+
+	c := &AvailableConditionController{
+		prober:                     &clientProber{discoveryClient: &http.Client{Transport: transport}},
+		MaxConcurrentProbesPerHost: limit,
+	}
+	discoveryURL := &url.URL{Scheme: "https", Host: "shared.example.com"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < numProbes; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.probe(context.Background(), discoveryURL, nil, "", ""); err != nil {
+				t.Errorf("unexpected probe error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if maxInFlight > limit {
+		t.Errorf("expected at most %d concurrent probes against the shared host, observed %d", limit, maxInFlight)
+	}
+	if maxInFlight < limit {
+		t.Errorf("expected concurrency to actually reach the configured limit of %d, observed %d", limit, maxInFlight)
+	}
+}
+
+// TestPauseStopsWorkerProcessing checks that a key enqueued while the controller is paused sits
+// untouched until Resume is called, rather than being processed (or dropped) immediately.
+func TestPauseStopsWorkerProcessing(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	service := newService("foo", "bar")
+	endpoints := newEndpointsWithAddress("foo", "bar")
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	c := &AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		serviceResolver:  &fakeServiceResolver{url: &url.URL{Scheme: "https", Host: "1.2.3.4:443"}},
+		prober:           &fakeProber{},
+		queue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "TestPauseStopsWorkerProcessing"),
+	}
+	c.pauseCond = sync.NewCond(&c.pauseMu)
+	c.syncFn = c.sync
+
+	c.Pause()
+	c.queue.Add("remote.group")
+
+	processed := make(chan bool, 1)
+	go func() {
+		processed <- c.processNextWorkItem()
+	}()
+
+	select {
+	case <-processed:
+		t.Fatal("expected processNextWorkItem to block while paused, but it returned")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if actions := fakeClient.Actions(); len(actions) != 0 {
+		t.Fatalf("expected no client actions while paused, got %v", actions)
+	}
+
+	c.Resume()
+
+	select {
+	case ok := <-processed:
+		if !ok {
+			t.Fatal("expected processNextWorkItem to report it's not time to quit")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected processNextWorkItem to unblock and process the key after Resume")
+	}
+
+	if actions := fakeClient.Actions(); len(actions) == 0 {
+		t.Fatal("expected a client action once the queued key was processed after Resume")
+	}
+}
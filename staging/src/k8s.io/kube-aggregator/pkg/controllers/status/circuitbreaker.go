@@ -0,0 +1,120 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// circuitBreakerState is the state of a single circuitBreaker.
+type circuitBreakerState int
+
+const (
+	circuitBreakerClosed circuitBreakerState = iota
+	circuitBreakerOpen
+	circuitBreakerHalfOpen
+)
+
+// circuitBreaker tracks consecutive discovery-probe failures against a single backend host, so
+// probe skips a backend that's already known to be down instead of repeating a failing probe
+// against it every sync.
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	state               circuitBreakerState
+	consecutiveFailures int
+	openedAt            time.Time
+	lastErr             error
+}
+
+// allow reports whether a probe against this breaker's host should proceed at now. A closed or
+// half-open breaker always allows it. An open breaker refuses until cooldown has elapsed since it
+// tripped, at which point it transitions to half-open and allows a single trial probe through.
+// lastErr is returned so the caller can report why the breaker was tripped when it refuses.
+func (b *circuitBreaker) allow(now time.Time, cooldown time.Duration) (ok bool, lastErr error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != circuitBreakerOpen {
+		return true, nil
+	}
+	if now.Sub(b.openedAt) < cooldown {
+		return false, b.lastErr
+	}
+	b.state = circuitBreakerHalfOpen
+	return true, nil
+}
+
+// recordSuccess closes the breaker and resets its failure count, e.g. after a half-open trial
+// probe succeeds.
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = circuitBreakerClosed
+	b.consecutiveFailures = 0
+	b.lastErr = nil
+}
+
+// recordFailure counts a probe failure, tripping the breaker open once threshold consecutive
+// failures have been seen, or immediately re-opening a half-open breaker whose trial probe also
+// failed.
+func (b *circuitBreaker) recordFailure(now time.Time, threshold int, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastErr = err
+	if b.state == circuitBreakerHalfOpen {
+		b.state = circuitBreakerOpen
+		b.openedAt = now
+		return
+	}
+
+	b.consecutiveFailures++
+	if b.consecutiveFailures >= threshold {
+		b.state = circuitBreakerOpen
+		b.openedAt = now
+	}
+}
+
+// circuitBreakerForHost returns the circuitBreaker tracking host, lazily creating one the first
+// time host is seen.
+func (c *AvailableConditionController) circuitBreakerForHost(host string) *circuitBreaker {
+	c.circuitBreakersMu.Lock()
+	defer c.circuitBreakersMu.Unlock()
+	if c.circuitBreakers == nil {
+		c.circuitBreakers = make(map[string]*circuitBreaker)
+	}
+	breaker, ok := c.circuitBreakers[host]
+	if !ok {
+		breaker = &circuitBreaker{}
+		c.circuitBreakers[host] = breaker
+	}
+	return breaker
+}
+
+// ErrCircuitBreakerOpen is returned by probe when Host's circuit breaker is open, short-circuiting
+// an actual probe attempt. LastErr is the failure that most recently tripped or re-tripped it.
+type ErrCircuitBreakerOpen struct {
+	Host    string
+	LastErr error
+}
+
+func (e *ErrCircuitBreakerOpen) Error() string {
+	return fmt.Sprintf("circuit breaker for %s is open due to a prior failure: %v", e.Host, e.LastErr)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"k8s.io/kube-aggregator/pkg/apis/apiregistration"
+	listers "k8s.io/kube-aggregator/pkg/client/listers/apiregistration/internalversion"
+)
+
+// GroupAvailability reports whether every APIService belonging to group (matched by Spec.Group)
+// has an Available condition of True, for dashboards that want to aggregate availability per API
+// group instead of per APIService. unavailable names every APIService in the group that isn't
+// Available -- including one with no Available condition at all, i.e. not yet checked -- sorted
+// for a deterministic report. A group with no APIServices at all is reported available with an
+// empty unavailable list, since there's nothing in it keeping it down.
+func GroupAvailability(lister listers.APIServiceLister, group string) (available bool, unavailable []string) {
+	apiServices, err := lister.List(labels.Everything())
+	if err != nil {
+		return false, []string{fmt.Sprintf("failed to list APIServices: %v", err)}
+	}
+
+	for _, apiService := range apiServices {
+		if apiService.Spec.Group != group {
+			continue
+		}
+		condition := apiregistration.GetAPIServiceConditionByType(apiService, apiregistration.Available)
+		if condition == nil || condition.Status != apiregistration.ConditionTrue {
+			unavailable = append(unavailable, apiService.Name)
+		}
+	}
+	sort.Strings(unavailable)
+	return len(unavailable) == 0, unavailable
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/tools/cache"
+
+	"k8s.io/kube-aggregator/pkg/apis/apiregistration"
+	listers "k8s.io/kube-aggregator/pkg/client/listers/apiregistration/internalversion"
+)
+
+// TestGroupAvailability checks that a group with a mix of an Available and an unavailable
+// APIService is reported unavailable, naming the culprit, while an unrelated group with only
+// healthy APIServices is unaffected.
+func TestGroupAvailability(t *testing.T) {
+	healthy := newRemoteAPIService("v1beta1.metrics.k8s.io")
+	healthy.Spec.Group = "metrics.k8s.io"
+	apiregistration.SetAPIServiceCondition(healthy, apiregistration.APIServiceCondition{
+		Type:   apiregistration.Available,
+		Status: apiregistration.ConditionTrue,
+	})
+
+	unhealthy := newRemoteAPIService("v1.metrics.k8s.io")
+	unhealthy.Spec.Group = "metrics.k8s.io"
+	apiregistration.SetAPIServiceCondition(unhealthy, apiregistration.APIServiceCondition{
+		Type:   apiregistration.Available,
+		Status: apiregistration.ConditionFalse,
+	})
+
+	otherGroup := newRemoteAPIService("v1.other.example.com")
+	otherGroup.Spec.Group = "other.example.com"
+	apiregistration.SetAPIServiceCondition(otherGroup, apiregistration.APIServiceCondition{
+		Type:   apiregistration.Available,
+		Status: apiregistration.ConditionTrue,
+	})
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	indexer.Add(healthy)
+	indexer.Add(unhealthy)
+	indexer.Add(otherGroup)
+	lister := listers.NewAPIServiceLister(indexer)
+
+	if available, unavailable := GroupAvailability(lister, "metrics.k8s.io"); available || !reflect.DeepEqual(unavailable, []string{"v1.metrics.k8s.io"}) {
+		t.Errorf("expected unavailable [v1.metrics.k8s.io], got available=%v unavailable=%v", available, unavailable)
+	}
+
+	if available, unavailable := GroupAvailability(lister, "other.example.com"); !available || len(unavailable) != 0 {
+		t.Errorf("expected the unrelated group to be available with no culprits, got available=%v unavailable=%v", available, unavailable)
+	}
+
+	if available, unavailable := GroupAvailability(lister, "no-such-group"); !available || len(unavailable) != 0 {
+		t.Errorf("expected a group with no APIServices to be reported available, got available=%v unavailable=%v", available, unavailable)
+	}
+}
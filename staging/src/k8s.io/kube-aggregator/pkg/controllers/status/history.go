@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/kube-aggregator/pkg/apis/apiregistration"
+)
+
+// maxAvailabilityHistoryEntries is how many of the most recent Available condition transitions
+// are kept in apiregistration.AvailabilityHistoryAnnotation, oldest first. Older entries are
+// dropped as new ones are recorded, so the annotation can't grow without bound on an APIService
+// that flaps indefinitely.
+const maxAvailabilityHistoryEntries = 10
+
+// availabilityHistoryEntry is one entry in apiregistration.AvailabilityHistoryAnnotation.
+type availabilityHistoryEntry struct {
+	Reason string      `json:"reason"`
+	Time   metav1.Time `json:"time"`
+}
+
+// recordAvailabilityTransition appends a transition to reason to apiService's
+// AvailabilityHistoryAnnotation, rotating out the oldest entry once there are more than
+// maxAvailabilityHistoryEntries, so operators can look back at an APIService's recent flapping
+// history without having to have been watching at the time. A malformed or missing existing
+// annotation is treated as an empty history rather than an error.
+func recordAvailabilityTransition(apiService *apiregistration.APIService, reason string, timestamp metav1.Time) {
+	var history []availabilityHistoryEntry
+	if existing := apiService.Annotations[apiregistration.AvailabilityHistoryAnnotation]; existing != "" {
+		// ignore unmarshal errors: a corrupt or foreign annotation value just starts a fresh history.
+		json.Unmarshal([]byte(existing), &history)
+	}
+
+	history = append(history, availabilityHistoryEntry{Reason: reason, Time: timestamp})
+	if len(history) > maxAvailabilityHistoryEntries {
+		history = history[len(history)-maxAvailabilityHistoryEntries:]
+	}
+
+	encoded, err := json.Marshal(history)
+	if err != nil {
+		// the entries are all plain strings and timestamps; marshaling them cannot fail.
+		return
+	}
+	if apiService.Annotations == nil {
+		apiService.Annotations = map[string]string{}
+	}
+	apiService.Annotations[apiregistration.AvailabilityHistoryAnnotation] = string(encoded)
+}
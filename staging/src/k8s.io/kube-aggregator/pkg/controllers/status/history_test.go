@@ -0,0 +1,71 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/kube-aggregator/pkg/apis/apiregistration"
+)
+
+func TestRecordAvailabilityTransitionGrowsAndRotates(t *testing.T) {
+	apiService := &apiregistration.APIService{}
+
+	for i := 0; i < maxAvailabilityHistoryEntries+3; i++ {
+		recordAvailabilityTransition(apiService, fmt.Sprintf("Reason%d", i), metav1.NewTime(metav1.Now().Time))
+	}
+
+	var history []availabilityHistoryEntry
+	raw := apiService.Annotations[apiregistration.AvailabilityHistoryAnnotation]
+	if err := json.Unmarshal([]byte(raw), &history); err != nil {
+		t.Fatalf("failed to unmarshal history annotation %q: %v", raw, err)
+	}
+
+	if len(history) != maxAvailabilityHistoryEntries {
+		t.Fatalf("expected history capped at %d entries, got %d: %v", maxAvailabilityHistoryEntries, len(history), history)
+	}
+	for i, entry := range history {
+		want := fmt.Sprintf("Reason%d", i+3)
+		if entry.Reason != want {
+			t.Errorf("entry %d: expected reason %q (oldest 3 should have rotated out), got %q", i, want, entry.Reason)
+		}
+	}
+}
+
+func TestRecordAvailabilityTransitionIgnoresCorruptExistingAnnotation(t *testing.T) {
+	apiService := &apiregistration.APIService{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				apiregistration.AvailabilityHistoryAnnotation: "not valid json",
+			},
+		},
+	}
+
+	recordAvailabilityTransition(apiService, "Passed", metav1.NewTime(metav1.Now().Time))
+
+	var history []availabilityHistoryEntry
+	if err := json.Unmarshal([]byte(apiService.Annotations[apiregistration.AvailabilityHistoryAnnotation]), &history); err != nil {
+		t.Fatalf("failed to unmarshal history annotation: %v", err)
+	}
+	if len(history) != 1 || history[0].Reason != "Passed" {
+		t.Fatalf("expected a fresh single-entry history, got %v", history)
+	}
+}
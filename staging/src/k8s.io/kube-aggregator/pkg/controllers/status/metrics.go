@@ -0,0 +1,88 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/kube-aggregator/pkg/apis/apiregistration"
+)
+
+const availabilitySubsystem = "aggregator"
+
+var (
+	// availableGauge reports 1 when an APIService's Available condition is True and 0 otherwise,
+	// labeled by APIService name. Deleted from when the APIService itself is deleted, so a
+	// long-lived aggregator doesn't accumulate a growing series for every APIService that has ever
+	// existed.
+	availableGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: availabilitySubsystem,
+			Name:      "apiservice_available",
+			Help:      "Whether an APIService's Available condition is currently True (1) or not (0), labeled by APIService name.",
+		},
+		[]string{"name"},
+	)
+
+	// reasonCounter counts every Available condition computed by sync, labeled by reason, so
+	// operators can trend failure modes over time instead of only seeing an APIService's current
+	// reason. Unlike availableGauge, it's never deleted on APIService deletion and isn't labeled
+	// by name -- it's a cluster-wide histogram of reasons, not a per-APIService series.
+	reasonCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: availabilitySubsystem,
+			Name:      "apiservice_availability_reason_total",
+			Help:      "Count of Available conditions computed for APIServices, labeled by reason.",
+		},
+		[]string{"reason"},
+	)
+)
+
+var registerMetrics sync.Once
+
+// Register registers this package's metrics with the default prometheus registry. It's
+// idempotent and safe to call from multiple call sites.
+func Register() {
+	registerMetrics.Do(func() {
+		prometheus.MustRegister(availableGauge)
+		prometheus.MustRegister(reasonCounter)
+	})
+}
+
+// deleteAvailabilityMetrics removes the availability metric series for an APIService, called
+// when the APIService itself is deleted so its series doesn't linger forever.
+func deleteAvailabilityMetrics(name string) {
+	availableGauge.DeleteLabelValues(name)
+}
+
+// recordAvailability sets the availability metric series for name to reflect status.
+func recordAvailability(name string, status apiregistration.ConditionStatus) {
+	value := 0.0
+	if status == apiregistration.ConditionTrue {
+		value = 1.0
+	}
+	availableGauge.WithLabelValues(name).Set(value)
+}
+
+// recordAvailabilityReason increments reasonCounter for reason, called once per Available
+// condition sync computes, independent of whether that condition actually changes anything on the
+// APIService.
+func recordAvailabilityReason(reason string) {
+	reasonCounter.WithLabelValues(reason).Inc()
+}
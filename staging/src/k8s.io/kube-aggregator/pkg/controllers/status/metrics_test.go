@@ -0,0 +1,168 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	v1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/kube-aggregator/pkg/apis/apiregistration"
+	"k8s.io/kube-aggregator/pkg/client/clientset_generated/internalclientset/fake"
+	listers "k8s.io/kube-aggregator/pkg/client/listers/apiregistration/internalversion"
+)
+
+// availabilityGaugeValue returns the current value of the availability gauge series for name, or
+// false if no series has been recorded for it.
+func availabilityGaugeValue(t *testing.T, name string) (float64, bool) {
+	t.Helper()
+	metricCh := make(chan prometheus.Metric, 16)
+	availableGauge.Collect(metricCh)
+	close(metricCh)
+	for metric := range metricCh {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, label := range m.GetLabel() {
+			if label.GetName() == "name" && label.GetValue() == name {
+				return m.GetGauge().GetValue(), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// reasonCounterValue returns the current count of the reason counter series for reason, or false
+// if no series has been recorded for it.
+func reasonCounterValue(t *testing.T, reason string) (float64, bool) {
+	t.Helper()
+	// reasonCounter accumulates a distinct series per reason across every test in this package
+	// that calls sync(), so its cardinality can exceed a small fixed buffer by the time this test
+	// runs; collect it from a goroutine so Collect's sends are drained concurrently instead of
+	// deadlocking against an unread, full channel.
+	metricCh := make(chan prometheus.Metric, 16)
+	go func() {
+		reasonCounter.Collect(metricCh)
+		close(metricCh)
+	}()
+	for metric := range metricCh {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, label := range m.GetLabel() {
+			if label.GetName() == "reason" && label.GetValue() == reason {
+				return m.GetCounter().GetValue(), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// TestSyncRecordsAvailabilityReasonCounts checks that sync increments reasonCounter for each
+// distinct reason it computes, including on repeated syncs that don't change the condition.
+func TestSyncRecordsAvailabilityReasonCounts(t *testing.T) {
+	healthyAPIService := newRemoteAPIService("healthy.group")
+	missingServiceAPIService := newRemoteAPIService("missing-service.group")
+	missingServiceAPIService.Spec.Service.Name = "does-not-exist"
+
+	service := newService("foo", "bar")
+	endpoints := newEndpointsWithAddress("foo", "bar")
+
+	fakeClient := fake.NewSimpleClientset(healthyAPIService, missingServiceAPIService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(healthyAPIService)
+	apiServiceIndexer.Add(missingServiceAPIService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	c := &AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		prober:           &fakeProber{},
+	}
+
+	// reasonCounter is a shared package-level series that every other test in this package also
+	// increments by calling sync(), so assert on the increase this test causes rather than an
+	// absolute count.
+	passedBefore, _ := reasonCounterValue(t, "Passed")
+	notFoundBefore, _ := reasonCounterValue(t, string(apiregistration.AvailabilityReasonServiceNotFound))
+
+	if err := c.sync("healthy.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.sync("missing-service.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// a second sync of the already-healthy APIService doesn't change its condition, but should
+	// still count toward the Passed reason.
+	if err := c.sync("healthy.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if value, ok := reasonCounterValue(t, "Passed"); !ok || value-passedBefore != 2 {
+		t.Errorf("expected reason Passed to be counted twice more, got a delta of %v (present=%v)", value-passedBefore, ok)
+	}
+	if value, ok := reasonCounterValue(t, string(apiregistration.AvailabilityReasonServiceNotFound)); !ok || value-notFoundBefore != 1 {
+		t.Errorf("expected reason ServiceNotFound to be counted once more, got a delta of %v (present=%v)", value-notFoundBefore, ok)
+	}
+}
+
+func TestDeleteAPIServiceRemovesAvailabilityMetric(t *testing.T) {
+	apiService := newRemoteAPIService("remote.group")
+	service := newService("foo", "bar")
+	endpoints := newEndpointsWithAddress("foo", "bar")
+
+	fakeClient := fake.NewSimpleClientset(apiService)
+	apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	apiServiceIndexer.Add(apiService)
+	serviceIndexer.Add(service)
+	endpointsIndexer.Add(endpoints)
+
+	c := &AvailableConditionController{
+		apiServiceClient: fakeClient.Apiregistration(),
+		apiServiceLister: listers.NewAPIServiceLister(apiServiceIndexer),
+		serviceLister:    v1listers.NewServiceLister(serviceIndexer),
+		endpointsLister:  v1listers.NewEndpointsLister(endpointsIndexer),
+		prober:           &fakeProber{},
+		queue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "AvailableConditionController"),
+	}
+	if err := c.sync("remote.group"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if value, ok := availabilityGaugeValue(t, "remote.group"); !ok || value != 1 {
+		t.Fatalf("expected the availability metric to read 1 after a successful sync, got %v (present=%v)", value, ok)
+	}
+
+	c.deleteAPIService(apiService)
+
+	if _, ok := availabilityGaugeValue(t, "remote.group"); ok {
+		t.Errorf("expected the availability metric series to be removed after the APIService is deleted")
+	}
+}
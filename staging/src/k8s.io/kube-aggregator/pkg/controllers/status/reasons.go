@@ -0,0 +1,52 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"strings"
+
+	"k8s.io/kube-aggregator/pkg/apis/apiregistration"
+)
+
+// AvailabilityFailure pairs a machine-readable AvailabilityReason with its human-readable
+// explanation. It exists to report more than one simultaneous cause of unavailability, which a
+// single APIServiceCondition's Reason/Message pair cannot represent on its own.
+type AvailabilityFailure struct {
+	Reason  apiregistration.AvailabilityReason
+	Message string
+}
+
+// CombineAvailabilityFailures merges one or more AvailabilityFailures into the Reason and
+// Message of a single APIServiceCondition. The Reason of the first failure is used as the
+// condition's Reason, since APIServiceCondition.Reason is defined to be a single CamelCase
+// word; all of the failure messages are concatenated into Message so that none of the
+// simultaneous causes is lost.
+//
+// probeBackend uses this for its Endpoints-derived checks (readiness, port match, local zone),
+// which -- unlike the Service/Endpoints lookups earlier in the same function -- are independent
+// of one another and can fail simultaneously, e.g. a Service with both a stale port and a
+// not-yet-ready majority of endpoints.
+func CombineAvailabilityFailures(failures []AvailabilityFailure) (reason string, message string) {
+	if len(failures) == 0 {
+		return "", ""
+	}
+	messages := make([]string, 0, len(failures))
+	for _, failure := range failures {
+		messages = append(messages, failure.Message)
+	}
+	return string(failures[0].Reason), strings.Join(messages, "; ")
+}
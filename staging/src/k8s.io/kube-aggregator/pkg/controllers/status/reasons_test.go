@@ -0,0 +1,42 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"testing"
+
+	"k8s.io/kube-aggregator/pkg/apis/apiregistration"
+)
+
+func TestCombineAvailabilityFailures(t *testing.T) {
+	if reason, message := CombineAvailabilityFailures(nil); reason != "" || message != "" {
+		t.Errorf("expected empty reason/message for no failures, got %q/%q", reason, message)
+	}
+
+	failures := []AvailabilityFailure{
+		{Reason: apiregistration.AvailabilityReasonServiceNotFound, Message: "service/foo in \"bar\" is not present"},
+		{Reason: apiregistration.AvailabilityReasonMissingEndpoints, Message: "endpoints for service/foo in \"bar\" have no addresses"},
+	}
+	reason, message := CombineAvailabilityFailures(failures)
+	if reason != string(apiregistration.AvailabilityReasonServiceNotFound) {
+		t.Errorf("expected reason of first failure, got %q", reason)
+	}
+	want := "service/foo in \"bar\" is not present; endpoints for service/foo in \"bar\" have no addresses"
+	if message != want {
+		t.Errorf("expected combined message %q, got %q", want, message)
+	}
+}
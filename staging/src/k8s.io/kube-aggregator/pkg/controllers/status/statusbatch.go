@@ -0,0 +1,75 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"sync"
+
+	"k8s.io/kube-aggregator/pkg/apis/apiregistration"
+	apiregistrationclient "k8s.io/kube-aggregator/pkg/client/clientset_generated/internalclientset/typed/apiregistration/internalversion"
+)
+
+// StatusBatcher coalesces APIService status updates that arrive for the same APIService before
+// Flush is called, so that only the most recently queued one is actually written. This cuts
+// down on API calls when many APIServices' availability changes at once, or when a single
+// APIService's availability flaps several times in a short window: earlier, now-stale updates
+// in the batch never reach the API server.
+//
+// sync calls UpdateStatus directly rather than through a StatusBatcher, since the workqueue
+// already needs a per-key error to decide whether to retry. StatusBatcher is for callers that
+// observe availability changes for many APIServices in bulk (for example, a periodic full
+// resync) and want to throttle the resulting write volume.
+type StatusBatcher struct {
+	client apiregistrationclient.APIServicesGetter
+
+	mutex   sync.Mutex
+	pending map[string]*apiregistration.APIService
+}
+
+// NewStatusBatcher returns a StatusBatcher that writes through client.
+func NewStatusBatcher(client apiregistrationclient.APIServicesGetter) *StatusBatcher {
+	return &StatusBatcher{
+		client:  client,
+		pending: map[string]*apiregistration.APIService{},
+	}
+}
+
+// Add queues apiService's current status to be written on the next Flush, replacing any update
+// already queued for the same name.
+func (b *StatusBatcher) Add(apiService *apiregistration.APIService) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.pending[apiService.Name] = apiService
+}
+
+// Flush writes the most recently queued status for each APIService added since the last Flush,
+// and returns any errors encountered, keyed by APIService name. Flush is safe to call
+// concurrently with Add.
+func (b *StatusBatcher) Flush() map[string]error {
+	b.mutex.Lock()
+	pending := b.pending
+	b.pending = map[string]*apiregistration.APIService{}
+	b.mutex.Unlock()
+
+	errs := map[string]error{}
+	for name, apiService := range pending {
+		if _, err := b.client.APIServices().UpdateStatus(apiService); err != nil {
+			errs[name] = err
+		}
+	}
+	return errs
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/kube-aggregator/pkg/apis/apiregistration"
+	"k8s.io/kube-aggregator/pkg/client/clientset_generated/internalclientset/fake"
+)
+
+func TestStatusBatcherCoalescesRepeatedUpdates(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&apiregistration.APIService{ObjectMeta: metav1.ObjectMeta{Name: "remote.group"}},
+		&apiregistration.APIService{ObjectMeta: metav1.ObjectMeta{Name: "other.group"}},
+	)
+	batcher := NewStatusBatcher(fakeClient.Apiregistration())
+
+	batcher.Add(&apiregistration.APIService{ObjectMeta: metav1.ObjectMeta{Name: "remote.group"}, Status: apiregistration.APIServiceStatus{Conditions: []apiregistration.APIServiceCondition{{Reason: "First"}}}})
+	batcher.Add(&apiregistration.APIService{ObjectMeta: metav1.ObjectMeta{Name: "remote.group"}, Status: apiregistration.APIServiceStatus{Conditions: []apiregistration.APIServiceCondition{{Reason: "Second"}}}})
+	batcher.Add(&apiregistration.APIService{ObjectMeta: metav1.ObjectMeta{Name: "other.group"}, Status: apiregistration.APIServiceStatus{Conditions: []apiregistration.APIServiceCondition{{Reason: "Passed"}}}})
+
+	if errs := batcher.Flush(); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	actions := fakeClient.Actions()
+	if len(actions) != 2 {
+		t.Fatalf("expected exactly 2 writes (one per distinct name), got %d: %v", len(actions), actions)
+	}
+
+	for _, action := range actions {
+		apiService := action.(clienttesting.UpdateAction).GetObject().(*apiregistration.APIService)
+		if apiService.Name == "remote.group" && apiService.Status.Conditions[0].Reason != "Second" {
+			t.Errorf("expected the coalesced write for remote.group to carry the latest update, got %v", apiService.Status.Conditions[0].Reason)
+		}
+	}
+}